@@ -0,0 +1,43 @@
+package iter
+
+import "errors"
+
+// IterateAck works like [Cursor.Iterate] but the next page is only
+// fetched after callback acknowledges the current one by calling ack.
+// If callback returns nil without calling ack, the same page is
+// delivered again, so processing can be retried before the cursor
+// advances. Until ack is called, [Cursor.Input] and [Cursor.Checkpoint]
+// point to the current page.
+func (d *Cursor[Input, Result]) IterateAck(callback func(response Result, ack func()) error) error {
+	for d.Next() {
+		fresh := d.fresh
+		response, err := d.Get()
+		if err != nil {
+			if errors.Is(err, ErrStop) {
+				return nil
+			}
+			return err
+		}
+
+		next, hasNext := d.input, d.next
+		d.input, d.next, d.fresh = d.last, true, fresh
+
+		acked := false
+		ack := func() {
+			if !acked {
+				d.input, d.next, d.fresh = next, hasNext, false
+				acked = true
+			}
+		}
+		for !acked {
+			if err := callback(response, ack); err != nil {
+				if errors.Is(err, ErrStop) {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package iter_test
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestIterateAck(t *testing.T) {
+	mockServer := httptest.NewServer(MockAPIHandler(3))
+	defer mockServer.Close()
+	iterator := simpleIterator(mockServer)
+
+	var deliveries [][]Record
+	attempts := 0
+	err := iterator.IterateAck(func(response []Record, ack func()) error {
+		deliveries = append(deliveries, response)
+		attempts++
+		// fail processing of every page once
+		if attempts%2 == 0 {
+			ack()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	expected := [][]Record{
+		{{1}, {2}}, {{1}, {2}},
+		{{3}}, {{3}},
+		{}, {},
+	}
+	if !reflect.DeepEqual(deliveries, expected) {
+		t.Errorf("unexpected deliveries: got %+v, want %+v", deliveries, expected)
+	}
+
+	t.Run("stop", func(t *testing.T) {
+		iterator.Reset()
+		calls := 0
+		err := iterator.IterateAck(func(response []Record, ack func()) error {
+			calls++
+			return iter.ErrStop
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if calls != 1 {
+			t.Errorf("expected one call, got %d", calls)
+		}
+	})
+}
+
+func TestIterateAckCheckpoint(t *testing.T) {
+	mockServer := httptest.NewServer(MockAPIHandler(3))
+	defer mockServer.Close()
+	iterator := simpleIterator(mockServer)
+
+	var checkpoint iter.Checkpoint[int]
+	err := iterator.IterateAck(func(response []Record, ack func()) error {
+		if len(response) > 0 && response[0].ID == 3 {
+			// crash before the page is acknowledged
+			checkpoint = iterator.Checkpoint()
+			return iter.ErrStop
+		}
+		ack()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	resumed := simpleIterator(mockServer)
+	resumed.Restore(checkpoint)
+	response, err := resumed.Get()
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if !reflect.DeepEqual(response, []Record{{3}}) {
+		t.Errorf("expected to resume at the unacknowledged page, got %+v", response)
+	}
+}
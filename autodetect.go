@@ -0,0 +1,126 @@
+package iter
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// paginationStyle returns link to the page following the page fetched
+// from input, or empty string if there is none.
+type paginationStyle func(input string, header http.Header, page map[string]any) string
+
+// paginationStyles are tried in order on the first response by
+// [NewAutoHTTP].
+var paginationStyles = []paginationStyle{
+	func(input string, header http.Header, page map[string]any) string {
+		return linkTarget(header, "next")
+	},
+	nextURLField("@odata.nextLink"),
+	nextURLField("nextLink"),
+	nextURLField("next_page_url"),
+	nextURLField("next"),
+	nextURLField("links.next"),
+	nextURLField("paging.next"),
+	nextURLField("_links.next.href"),
+	pageTokenField("nextPageToken", "pageToken"),
+	pageTokenField("next_page_token", "page_token"),
+	pageTokenField("response_metadata.next_cursor", "cursor"),
+	pageTokenField("next_cursor", "cursor"),
+	pageTokenField("continuationToken", "continuationToken"),
+	hasMore("data", "id", "starting_after"),
+}
+
+// NewAutoHTTP creates a cursor over JSON objects of an API with unknown
+// pagination, for quick exploration of unfamiliar APIs. The pagination
+// style is detected from the first response, trying:
+//   - the Link header with rel="next",
+//   - next page URL fields such as "next" or "@odata.nextLink",
+//   - page token fields such as "nextPageToken" or "next_cursor", sent
+//     back as "pageToken" or "cursor" query parameter,
+//   - "has_more" with the "id" of the last item of "data", sent back as
+//     "starting_after" query parameter.
+//
+// The iteration stops after the first page if no style is detected. Set
+// Next to use given pagination instead.
+func NewAutoHTTP(config HTTPConfig[map[string]any]) *Cursor[string, map[string]any] {
+	if config.Next != nil {
+		return NewHTTP(config)
+	}
+
+	var detected paginationStyle
+	return newHTTP(config, func(input string, header http.Header, page map[string]any) string {
+		if detected != nil {
+			return detected(input, header, page)
+		}
+		for _, style := range paginationStyles {
+			if next := style(input, header, page); next != "" {
+				detected = style
+				return next
+			}
+		}
+		return ""
+	})
+}
+
+func nextURLField(path string) paginationStyle {
+	return func(input string, header http.Header, page map[string]any) string {
+		next, _ := lookup(page, path).(string)
+		if u, err := url.Parse(next); err != nil || (u.Scheme == "" && !strings.HasPrefix(next, "/") && !strings.HasPrefix(next, "?")) {
+			return ""
+		}
+		return next
+	}
+}
+
+func pageTokenField(path, param string) paginationStyle {
+	return func(input string, header http.Header, page map[string]any) string {
+		token, _ := lookup(page, path).(string)
+		if token == "" {
+			return ""
+		}
+		next, err := SetQuery(input, url.Values{param: {token}})
+		if err != nil {
+			return ""
+		}
+		return next
+	}
+}
+
+func hasMore(items, id, param string) paginationStyle {
+	return func(input string, header http.Header, page map[string]any) string {
+		more, _ := page["has_more"].(bool)
+		list, _ := page[items].([]any)
+		if !more || len(list) == 0 {
+			return ""
+		}
+		last, _ := list[len(list)-1].(map[string]any)
+		value, _ := last[id].(string)
+		if value == "" {
+			return ""
+		}
+		next, err := SetQuery(input, url.Values{param: {value}})
+		if err != nil {
+			return ""
+		}
+		return next
+	}
+}
+
+// lookup returns value at dot separated path of nested objects. Keys
+// containing dots, such as "@odata.nextLink", are matched as a whole.
+func lookup(value any, path string) any {
+	if object, ok := value.(map[string]any); ok {
+		if v, ok := object[path]; ok {
+			return v
+		}
+	}
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
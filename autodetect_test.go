@@ -0,0 +1,103 @@
+package iter_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"go.teddydd.me/iter"
+	"go.teddydd.me/iter/itertest"
+)
+
+func collectIDs(t *testing.T, iterator *iter.Cursor[string, map[string]any], field string) []float64 {
+	t.Helper()
+
+	var ids []float64
+	err := iterator.Iterate(func(page map[string]any) error {
+		items, _ := page[field].([]any)
+		for _, item := range items {
+			ids = append(ids, item.(map[string]any)["id"].(float64))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return ids
+}
+
+func TestNewAutoHTTP(t *testing.T) {
+	expected := []float64{1, 2, 3, 4, 5}
+
+	t.Run("page token", func(t *testing.T) {
+		server := itertest.NewServer(itertest.ServerConfig{Records: 5, PageSize: 2, Style: itertest.PageToken})
+		defer server.Close()
+
+		ids := collectIDs(t, iter.NewAutoHTTP(iter.HTTPConfig[map[string]any]{URL: server.URL + "?limit=2"}), "records")
+		if !reflect.DeepEqual(ids, expected) {
+			t.Errorf("unexpected ids: %v", ids)
+		}
+	})
+
+	t.Run("next link field", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			page := map[string]any{"value": []map[string]int{{"id": 2*n + 1}, {"id": 2*n + 2}}}
+			if n < 1 {
+				page["@odata.nextLink"] = fmt.Sprintf("http://%s/?page=%d", r.Host, n+1)
+			}
+			json.NewEncoder(w).Encode(page)
+		}))
+		defer mockServer.Close()
+
+		ids := collectIDs(t, iter.NewAutoHTTP(iter.HTTPConfig[map[string]any]{URL: mockServer.URL}), "value")
+		if !reflect.DeepEqual(ids, []float64{1, 2, 3, 4}) {
+			t.Errorf("unexpected ids: %v", ids)
+		}
+	})
+
+	t.Run("has more", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			after, _ := strconv.Atoi(r.URL.Query().Get("starting_after"))
+			var data []map[string]string
+			for id := after + 1; id <= 5 && len(data) < 2; id++ {
+				data = append(data, map[string]string{"id": strconv.Itoa(id)})
+			}
+			json.NewEncoder(w).Encode(map[string]any{"data": data, "has_more": after+2 < 5})
+		}))
+		defer mockServer.Close()
+
+		var ids []string
+		err := iter.NewAutoHTTP(iter.HTTPConfig[map[string]any]{URL: mockServer.URL}).Iterate(func(page map[string]any) error {
+			for _, item := range page["data"].([]any) {
+				ids = append(ids, item.(map[string]any)["id"].(string))
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(ids, []string{"1", "2", "3", "4", "5"}) {
+			t.Errorf("unexpected ids: %v", ids)
+		}
+	})
+
+	t.Run("override", func(t *testing.T) {
+		server := itertest.NewServer(itertest.ServerConfig{Records: 5, PageSize: 2, Style: itertest.PageToken})
+		defer server.Close()
+
+		ids := collectIDs(t, iter.NewAutoHTTP(iter.HTTPConfig[map[string]any]{
+			URL: server.URL,
+			Next: func(header http.Header, page map[string]any) string {
+				return ""
+			},
+		}), "records")
+		if !reflect.DeepEqual(ids, expected[:2]) {
+			t.Errorf("unexpected ids: %v", ids)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package iter
+
+import "context"
+
+// Pager is implemented by Azure SDK pagers (runtime.Pager).
+type Pager[Page any] interface {
+	More() bool
+	NextPage(ctx context.Context) (Page, error)
+}
+
+// NewPager creates a cursor over Azure SDK style pagers. newPager should
+// create a pager starting at the continuation token (marker), nil for
+// the first page, and continuation should return the token of the page
+// following the given one (NextMarker, NextLink).
+//
+// The token is used as input, so the iteration can be checkpointed and
+// resumed. A new pager is created on Reset and whenever the input does
+// not match the position of the current pager.
+func NewPager[Page any](
+	ctx context.Context,
+	newPager func(token *string) Pager[Page],
+	continuation func(page Page) *string,
+) *Cursor[*string, Page] {
+	var (
+		pager Pager[Page]
+		next  *string
+	)
+
+	return New(Config[*string, Page]{
+		HasNext: func(result Page) (*string, bool) {
+			return next, pager.More()
+		},
+		FetchNext: func(input *string) (Page, error) {
+			if pager == nil || !sameToken(input, next) {
+				pager = newPager(input)
+			}
+
+			if !pager.More() {
+				var zero Page
+				next = input
+				return zero, nil
+			}
+
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				pager = nil
+				return page, err
+			}
+
+			next = continuation(page)
+			return page, nil
+		},
+		GetFirstInput: func() *string {
+			pager, next = nil, nil
+			return nil
+		},
+	})
+}
+
+func sameToken(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,93 @@
+package iter_test
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+type blobPage struct {
+	Blobs      []string
+	NextMarker *string
+}
+
+// fakePager serves blobs two at a time, using the offset as marker.
+type fakePager struct {
+	blobs  []string
+	offset int
+	done   bool
+}
+
+func (p *fakePager) More() bool {
+	return !p.done
+}
+
+func (p *fakePager) NextPage(ctx context.Context) (blobPage, error) {
+	end := p.offset + 2
+	if end >= len(p.blobs) {
+		end = len(p.blobs)
+		p.done = true
+	}
+
+	page := blobPage{Blobs: p.blobs[p.offset:end]}
+	p.offset = end
+	if !p.done {
+		marker := strconv.Itoa(end)
+		page.NextMarker = &marker
+	}
+	return page, nil
+}
+
+func TestNewPager(t *testing.T) {
+	blobs := []string{"a", "b", "c", "d", "e"}
+	created := 0
+	newPager := func(token *string) iter.Pager[blobPage] {
+		created++
+		pager := &fakePager{blobs: blobs}
+		if token != nil {
+			pager.offset, _ = strconv.Atoi(*token)
+		}
+		return pager
+	}
+	continuation := func(page blobPage) *string {
+		return page.NextMarker
+	}
+
+	iterator := iter.NewPager(context.Background(), newPager, continuation)
+
+	var results []string
+	err := iterator.Iterate(func(response blobPage) error {
+		results = append(results, response.Blobs...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(results, blobs) {
+		t.Errorf("unexpected blobs: %+v", results)
+	}
+	if created != 1 {
+		t.Errorf("expected single pager, got %d", created)
+	}
+
+	t.Run("resume from checkpoint", func(t *testing.T) {
+		marker := "2"
+		iterator := iter.NewPager(context.Background(), newPager, continuation)
+		iterator.Restore(iter.Checkpoint[*string]{Input: &marker})
+
+		var results []string
+		err := iterator.Iterate(func(response blobPage) error {
+			results = append(results, response.Blobs...)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(results, blobs[2:]) {
+			t.Errorf("unexpected blobs: %+v", results)
+		}
+	})
+}
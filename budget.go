@@ -0,0 +1,52 @@
+package iter
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrBudgetExhausted is returned by fetches of cursors using [Budget]
+// once the budget is spent.
+var ErrBudgetExhausted = errors.New("budget exhausted")
+
+// Budget returns middleware accounting cost of fetched results, e.g.
+// API credits reported in the response. Once the cumulative cost
+// reaches budget, further fetches fail with ErrBudgetExhausted without
+// calling FetchNext. The result that exceeds the budget is still
+// returned. The budget is shared by all iterations of the cursor,
+// including after Reset. A page fetched again replaces the cost of the
+// last page.
+func Budget[Input, Result any](budget int, cost func(result Result) int) Middleware[Input, Result] {
+	return func(config Config[Input, Result]) Config[Input, Result] {
+		var (
+			spent   int
+			before  int
+			refetch bool
+		)
+
+		config.onRefetch(func() {
+			refetch = true
+		})
+
+		config.wrapFetch(func(fetchNext func(input Input) (Result, error)) func(input Input) (Result, error) {
+			return func(input Input) (Result, error) {
+				base := spent
+				if refetch {
+					base, refetch = before, false
+				}
+				if base >= budget {
+					var zero Result
+					return zero, fmt.Errorf("%w: spent %d of %d", ErrBudgetExhausted, base, budget)
+				}
+
+				result, err := fetchNext(input)
+				if err == nil {
+					before, spent = base, base+cost(result)
+				}
+				return result, err
+			}
+		})
+
+		return config
+	}
+}
@@ -0,0 +1,47 @@
+package iter_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestBudget(t *testing.T) {
+	var fetched int
+	config := pagesConfig([][]int{{1, 2}, {3, 4, 5}, {6}, {7}})
+	fetchNext := config.FetchNext
+	config.FetchNext = func(input int) ([]int, error) {
+		fetched++
+		return fetchNext(input)
+	}
+
+	// every item costs one credit
+	iterator := iter.New(iter.Use(config, iter.Budget[int](4, func(result []int) int {
+		return len(result)
+	})))
+
+	pages, err := iterator.Collect()
+	if !errors.Is(err, iter.ErrBudgetExhausted) {
+		t.Errorf("expected ErrBudgetExhausted, got %v", err)
+	}
+	if !reflect.DeepEqual(pages, [][]int{{1, 2}, {3, 4, 5}}) {
+		t.Errorf("unexpected pages: %v", pages)
+	}
+	if fetched != 2 {
+		t.Errorf("expected 2 fetches, got %d", fetched)
+	}
+}
+
+func TestBudgetRetry(t *testing.T) {
+	config := pagesConfig([][]int{{1, 2}, {3, 4}, {5}})
+	iterator := iter.New(iter.Use(config, iter.Budget[int](5, func(result []int) int {
+		return len(result)
+	})))
+
+	pages, err := collectRetrying(iterator)
+	if err != nil || len(pages) != 3 {
+		t.Errorf("expected retried pages within budget, got %v, %v", pages, err)
+	}
+}
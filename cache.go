@@ -0,0 +1,48 @@
+package iter
+
+import (
+	"net/http"
+	"sync"
+)
+
+// CachedResponse is a decompressed response body stored with its
+// validators.
+type CachedResponse struct {
+	ETag         string
+	LastModified string
+	Header       http.Header
+	Body         []byte
+}
+
+// Cache stores responses by URL for conditional requests.
+type Cache interface {
+	Get(url string) (CachedResponse, bool)
+	Put(url string, response CachedResponse)
+}
+
+// MemoryCache is a Cache keeping responses in memory. It is safe for
+// concurrent use.
+type MemoryCache struct {
+	mu        sync.Mutex
+	responses map[string]CachedResponse
+}
+
+// NewMemoryCache creates an empty cache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{responses: make(map[string]CachedResponse)}
+}
+
+// Get returns the response cached for url.
+func (c *MemoryCache) Get(url string) (CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	response, ok := c.responses[url]
+	return response, ok
+}
+
+// Put stores the response for url.
+func (c *MemoryCache) Put(url string, response CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.responses[url] = response
+}
@@ -0,0 +1,109 @@
+package iter_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestNewHTTPCache(t *testing.T) {
+	var requests, notModified int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		etag := `"` + r.URL.Path + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/" {
+			w.Header().Set("Link", `</2>; rel="next"`)
+			fmt.Fprint(w, `{"numbers": [1, 2]}`)
+			return
+		}
+		fmt.Fprint(w, `{"numbers": [3]}`)
+	}))
+	defer mockServer.Close()
+
+	cache := iter.NewMemoryCache()
+	cursor := iter.NewHTTP(iter.HTTPConfig[numberPage]{URL: mockServer.URL + "/", Cache: cache})
+
+	for i := 0; i < 2; i++ {
+		var numbers []int
+		err := cursor.Iterate(func(page numberPage) error {
+			numbers = append(numbers, page.Numbers...)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(numbers) != "[1 2 3]" {
+			t.Errorf("run %d: unexpected numbers: %v", i, numbers)
+		}
+		cursor.Reset()
+	}
+
+	if requests != 4 || notModified != 2 {
+		t.Errorf("unexpected requests: %d, not modified: %d", requests, notModified)
+	}
+	if _, ok := cache.Get(mockServer.URL + "/2"); !ok {
+		t.Error("page not cached")
+	}
+}
+
+type stalePage struct {
+	Numbers []int `json:"numbers"`
+	Stale   error `json:"-"`
+}
+
+func (p *stalePage) MarkStale(err error) {
+	p.Stale = err
+}
+
+func TestNewHTTPStaleIfError(t *testing.T) {
+	var failing atomic.Bool
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"numbers": [1, 2]}`)
+	}))
+	defer mockServer.Close()
+
+	cursor := iter.NewHTTP(iter.HTTPConfig[stalePage]{
+		URL:          mockServer.URL,
+		Cache:        iter.NewMemoryCache(),
+		StaleIfError: true,
+	})
+
+	page, err := cursor.Get()
+	if err != nil || page.Stale != nil {
+		t.Fatalf("unexpected fresh page: %+v, %v", page, err)
+	}
+
+	failing.Store(true)
+	cursor.Reset()
+	page, err = cursor.Get()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var statusErr *iter.StatusError
+	if fmt.Sprint(page.Numbers) != "[1 2]" || !errors.As(page.Stale, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected stale page, got %+v", page)
+	}
+
+	uncached := iter.NewHTTP(iter.HTTPConfig[stalePage]{URL: mockServer.URL, StaleIfError: true, Cache: iter.NewMemoryCache()})
+	if _, err := uncached.Get(); !errors.As(err, &statusErr) {
+		t.Errorf("expected status error without cached page, got %v", err)
+	}
+}
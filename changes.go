@@ -0,0 +1,114 @@
+package iter
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ChangeKind is the kind of a [Change].
+type ChangeKind int
+
+const (
+	// Upsert items were created or updated.
+	Upsert ChangeKind = iota + 1
+	// Delete items are tombstones of deleted items.
+	Delete
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Upsert:
+		return "upsert"
+	case Delete:
+		return "delete"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Change is an item of a change feed classified by [Changes].
+type Change[T any] struct {
+	Kind ChangeKind
+	Item T
+}
+
+// Changes returns stream of changes of sync APIs, classifying items of
+// the stream as deletions when deleted returns true (e.g. for
+// deleted=true flags) and as upserts otherwise.
+func Changes[T any](stream *Stream[T], deleted func(item T) bool) *Stream[Change[T]] {
+	return newStream(func() (Change[T], error) {
+		item, err := stream.Get()
+		if err != nil {
+			return Change[T]{}, err
+		}
+		return classify(item, deleted), nil
+	}, stream.Reset)
+}
+
+func classify[T any](item T, deleted func(item T) bool) Change[T] {
+	if deleted(item) {
+		return Change[T]{Kind: Delete, Item: item}
+	}
+	return Change[T]{Kind: Upsert, Item: item}
+}
+
+// OffsetChange is a change along with the offset committing it.
+type OffsetChange[Input, T any] struct {
+	Change[T]
+	Offset ResumeToken[Input]
+}
+
+// ChangeFeed is a stream of changes with commit offsets, mirroring
+// consumer group semantics for paginated change feeds: committing the
+// offset of a change persists progress up to and including it.
+type ChangeFeed[Input, T any] struct {
+	*Stream[OffsetChange[Input, T]]
+	store CheckpointStore
+	key   string
+}
+
+// NewChangeFeed creates a change feed of items of the cursor,
+// classified with deleted as in [Changes]. It resumes after the offset
+// committed in store under key, if any.
+func NewChangeFeed[Input, T any](
+	cursor *Cursor[Input, []T],
+	deleted func(item T) bool,
+	store CheckpointStore,
+	key string,
+) (*ChangeFeed[Input, T], error) {
+	data, err := store.Load(key)
+	if err != nil {
+		return nil, err
+	}
+	var token *ResumeToken[Input]
+	if data != nil {
+		token = new(ResumeToken[Input])
+		if err := json.Unmarshal(data, token); err != nil {
+			return nil, err
+		}
+	}
+
+	items := ResumeItems(cursor, token)
+	stream := newStream(func() (OffsetChange[Input, T], error) {
+		item, err := items.Get()
+		if err != nil {
+			return OffsetChange[Input, T]{}, err
+		}
+		return OffsetChange[Input, T]{
+			Change: classify(item.Item, deleted),
+			Offset: item.Token,
+		}, nil
+	}, items.Reset)
+
+	return &ChangeFeed[Input, T]{Stream: stream, store: store, key: key}, nil
+}
+
+// Commit persists the offset, so the feed created again resumes right
+// after the change it belongs to. Inputs are encoded like in
+// [Checkpoint], so codecs and versions registered for them apply.
+func (f *ChangeFeed[Input, T]) Commit(offset ResumeToken[Input]) error {
+	data, err := json.Marshal(offset)
+	if err != nil {
+		return err
+	}
+	return f.store.Save(f.key, data)
+}
@@ -0,0 +1,79 @@
+package iter_test
+
+import (
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestChanges(t *testing.T) {
+	type record struct {
+		ID      int
+		Deleted bool
+	}
+	pages := [][]record{{{1, false}, {2, true}}, {{3, false}}}
+
+	stream := iter.Changes(iter.Items(iter.New(pagesConfig(pages))), func(r record) bool {
+		return r.Deleted
+	})
+	changes, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []iter.Change[record]{
+		{Kind: iter.Upsert, Item: record{1, false}},
+		{Kind: iter.Delete, Item: record{2, true}},
+		{Kind: iter.Upsert, Item: record{3, false}},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("unexpected changes: %+v", changes)
+	}
+	if iter.Delete.String() != "delete" {
+		t.Errorf("unexpected kind name: %v", iter.Delete)
+	}
+}
+
+func TestChangeFeed(t *testing.T) {
+	store := iter.NewMemoryStore()
+	pages := [][]int{{1, -2}, {3, -4}, {5}}
+	deleted := func(id int) bool { return id < 0 }
+
+	feed, err := iter.NewChangeFeed(iter.New(pagesConfig(pages)), deleted, store, "feed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		change, err := feed.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// only the first two changes are committed
+		if i < 2 {
+			if err := feed.Commit(change.Offset); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	feed, err = iter.NewChangeFeed(iter.New(pagesConfig(pages)), deleted, store, "feed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := feed.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var items []int
+	for _, change := range changes {
+		if (change.Kind == iter.Delete) != (change.Item < 0) {
+			t.Errorf("unexpected kind of %d: %v", change.Item, change.Kind)
+		}
+		items = append(items, change.Item)
+	}
+	if !reflect.DeepEqual(items, []int{3, -4, 5}) {
+		t.Errorf("unexpected resumed changes: %v", items)
+	}
+}
@@ -0,0 +1,160 @@
+package iter
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+// Codec converts cursor inputs to and from bytes. Register one with
+// [RegisterCodec] for input types that do not round-trip through
+// encoding/json.
+type Codec[Input any] interface {
+	Marshal(input Input) ([]byte, error)
+	Unmarshal(data []byte) (Input, error)
+}
+
+var codecs sync.Map
+
+// RegisterCodec registers codec used to (un)marshal checkpoints of
+// cursors with given Input type. Inputs of types without registered
+// codec are encoded with encoding/json.
+func RegisterCodec[Input any](codec Codec[Input]) {
+	codecs.Store(typeOf[Input](), codec)
+}
+
+func codecFor[Input any]() Codec[Input] {
+	codec, ok := codecs.Load(typeOf[Input]())
+	if !ok {
+		return nil
+	}
+	return codec.(Codec[Input])
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// Checkpoint is the state of a [Cursor] that can be persisted and
+// restored later to resume the iteration.
+type Checkpoint[Input any] struct {
+	// Input is used to fetch the next Result.
+	Input Input
+	// Done reports whether the cursor was depleted.
+	Done bool
+	// Fresh reports that no Result was fetched yet, so Input may be a
+	// placeholder not passed to [Config.Start] yet.
+	Fresh bool
+}
+
+type jsonCheckpoint struct {
+	Version int             `json:"version,omitempty"`
+	Input   json.RawMessage `json:"input"`
+	Done    bool            `json:"done"`
+	Fresh   bool            `json:"fresh,omitempty"`
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (c Checkpoint[Input]) MarshalJSON() ([]byte, error) {
+	var (
+		input []byte
+		err   error
+	)
+	if codec := codecFor[Input](); codec == nil {
+		input, err = json.Marshal(c.Input)
+	} else {
+		input, err = marshalEncoded(codec, c.Input)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(jsonCheckpoint{
+		Version: versionFor[Input]().version,
+		Input:   input,
+		Done:    c.Done,
+		Fresh:   c.Fresh,
+	})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. Checkpoints written with
+// older version of the Input type are upgraded with migrations
+// registered by [RegisterVersion].
+func (c *Checkpoint[Input]) UnmarshalJSON(data []byte) error {
+	var checkpoint jsonCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return err
+	}
+
+	codec := codecFor[Input]()
+	input := []byte(checkpoint.Input)
+	if codec != nil {
+		if err := json.Unmarshal(checkpoint.Input, &input); err != nil {
+			return err
+		}
+	}
+
+	input, err := migrate[Input](checkpoint.Version, input)
+	if err != nil {
+		return err
+	}
+
+	c.Done, c.Fresh = checkpoint.Done, checkpoint.Fresh
+	if codec == nil {
+		return json.Unmarshal(input, &c.Input)
+	}
+	c.Input, err = codec.Unmarshal(input)
+	return err
+}
+
+func marshalEncoded[Input any](codec Codec[Input], input Input) ([]byte, error) {
+	data, err := codec.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
+// Checkpoint returns the current state of the cursor.
+func (d *Cursor[Input, Result]) Checkpoint() Checkpoint[Input] {
+	return Checkpoint[Input]{
+		Input: d.input,
+		Done:  !d.next,
+		Fresh: d.fresh,
+	}
+}
+
+// Restore sets the state of the cursor from the checkpoint, so the
+// next Get continues where the checkpointed cursor stopped. Restored
+// input is used as is, without calling [Config.Start], and fetched
+// with FetchNext. Fresh checkpoints, taken before the first Result was
+// fetched, reset the cursor instead, so the iteration starts over with
+// Start and FetchFirst.
+func (d *Cursor[Input, Result]) Restore(checkpoint Checkpoint[Input]) {
+	if checkpoint.Fresh && !checkpoint.Done {
+		d.Reset()
+		return
+	}
+
+	d.input = checkpoint.Input
+	d.next = !checkpoint.Done
+	d.started = true
+	d.fresh = false
+	d.page = 0
+	d.refetch = nil
+	d.rejected = false
+	d.snapshot = ""
+}
+
+// Continue creates a cursor continuing after the last Result obtained
+// elsewhere, e.g. handed over by another system. The next input is
+// derived from it with HasNext. Reset starts over from GetFirstInput.
+func Continue[Input, Result any](config Config[Input, Result], last Result) *Cursor[Input, Result] {
+	cursor := New(config)
+	if config.Normalize != nil {
+		last = config.Normalize(last)
+	}
+	input, ok := config.HasNext(last)
+	cursor.Restore(Checkpoint[Input]{Input: input, Done: !ok})
+	return cursor
+}
@@ -0,0 +1,165 @@
+package iter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestCheckpoint(t *testing.T) {
+	mockServer := httptest.NewServer(MockAPIHandler(5))
+	defer mockServer.Close()
+
+	iterator := simpleIterator(mockServer)
+	if _, err := iterator.Get(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(iterator.Checkpoint())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"input":2,"done":false}` {
+		t.Errorf("unexpected checkpoint: %s", data)
+	}
+
+	var checkpoint iter.Checkpoint[int]
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	restored := simpleIterator(mockServer)
+	restored.Restore(checkpoint)
+	results, err := restored.Get()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(results, []Record{{3}, {4}}) {
+		t.Errorf("expected to continue after checkpoint, got %+v", results)
+	}
+
+	t.Run("depleted", func(t *testing.T) {
+		if err := iterator.Iterate(func(response []Record) error { return nil }); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		restored := simpleIterator(mockServer)
+		restored.Restore(iterator.Checkpoint())
+		if restored.Next() {
+			t.Errorf("restored depleted cursor should not have next")
+		}
+	})
+}
+
+type offset struct {
+	value int
+}
+
+type offsetCodec struct{}
+
+func (offsetCodec) Marshal(input offset) ([]byte, error) {
+	return []byte(strconv.Itoa(input.value)), nil
+}
+
+func (offsetCodec) Unmarshal(data []byte) (offset, error) {
+	value, err := strconv.Atoi(string(data))
+	return offset{value: value}, err
+}
+
+func TestCheckpointCodec(t *testing.T) {
+	iter.RegisterCodec[offset](offsetCodec{})
+
+	data, err := json.Marshal(iter.Checkpoint[offset]{Input: offset{value: 42}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var checkpoint iter.Checkpoint[offset]
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if checkpoint.Input.value != 42 {
+		t.Errorf("expected input to round-trip, got %+v from %s", checkpoint, data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"input":"bm90IGEgbnVtYmVy"}`), &checkpoint); err == nil {
+		t.Errorf("expected codec error")
+	}
+}
+
+func TestContinue(t *testing.T) {
+	type page struct {
+		Items []int
+		Next  int
+	}
+	pages := []page{{[]int{1}, 1}, {[]int{2}, 2}, {[]int{3}, 0}}
+	config := iter.Config[int, page]{
+		HasNext: func(result page) (int, bool) {
+			return result.Next, result.Next != 0
+		},
+		FetchNext: func(input int) (page, error) {
+			return pages[input], nil
+		},
+		GetFirstInput: func() int {
+			return 0
+		},
+	}
+
+	results, err := iter.Continue(config, pages[0]).Collect()
+	if err != nil || !reflect.DeepEqual(results, pages[1:]) {
+		t.Errorf("unexpected results: %v, %v", results, err)
+	}
+
+	results, err = iter.Continue(config, pages[2]).Collect()
+	if err != nil || len(results) != 0 {
+		t.Errorf("expected depleted cursor, got %v, %v", results, err)
+	}
+}
+
+func TestCheckpointBeforeStart(t *testing.T) {
+	type key struct{}
+	var calls []string
+	config := pagesConfig([][]int{{1}, {2}, {3}})
+	fetchNext := config.FetchNext
+	config.Start = func(ctx context.Context, input int) (int, error) {
+		calls = append(calls, "start "+ctx.Value(key{}).(string))
+		// the placeholder is replaced by a scroll starting at the second page
+		return input + 1, nil
+	}
+	config.FetchFirst = func(input int) ([]int, error) {
+		calls = append(calls, "first")
+		return fetchNext(input)
+	}
+
+	checkpoint := iter.New(config).Checkpoint()
+	if !checkpoint.Fresh {
+		t.Fatalf("expected fresh checkpoint, got %+v", checkpoint)
+	}
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored iter.Checkpoint[int]
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+
+	cursor := iter.New(config)
+	cursor.Restore(restored)
+	ctx := context.WithValue(context.Background(), key{}, "ctx")
+	page, err := cursor.GetContext(ctx)
+	if err != nil || !reflect.DeepEqual(page, []int{2}) {
+		t.Fatalf("unexpected page: %v, %v", page, err)
+	}
+	if expected := []string{"start ctx", "first"}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+	if cursor.Checkpoint().Fresh {
+		t.Error("expected checkpoint after the first page not to be fresh")
+	}
+}
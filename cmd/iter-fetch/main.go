@@ -0,0 +1,157 @@
+// Command iter-fetch pages through an HTTP API and writes the items as
+// JSON lines, e.g.
+//
+//	iter-fetch -url 'https://api.example.com/items' -page-size 100 \
+//		-token-field next_page_token -token-param page_token -items items
+//
+// Pages are followed with the Link header, or the page token field if
+// set, or the pagination detected by iter.NewAutoHTTP.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.teddydd.me/iter"
+)
+
+type headers http.Header
+
+func (h headers) String() string {
+	return fmt.Sprint(http.Header(h))
+}
+
+func (h headers) Set(value string) error {
+	key, value, ok := strings.Cut(value, ":")
+	if !ok {
+		return errors.New(`header must be "Key: value"`)
+	}
+	http.Header(h).Add(strings.TrimSpace(key), strings.TrimSpace(value))
+	return nil
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "iter-fetch:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("iter-fetch", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var (
+		rawURL     = flags.String("url", "", "URL of the first page")
+		pageSize   = flags.Int("page-size", 0, "page size sent as size parameter, if positive")
+		sizeParam  = flags.String("size-param", "limit", "query parameter of the page size")
+		tokenField = flags.String("token-field", "", "dot separated path of the next page token in responses")
+		tokenParam = flags.String("token-param", "page_token", "query parameter of the page token")
+		items      = flags.String("items", "", "dot separated path of the items in responses, pages are written whole if empty")
+		maxPages   = flags.Int("max-pages", 0, "stop after this many pages, if positive")
+		userAgent  = flags.String("user-agent", "iter-fetch", "User-Agent header")
+		progress   = flags.Bool("progress", false, "report progress to standard error")
+		header     = headers{}
+	)
+	flags.Var(header, "header", `request header "Key: value", can be repeated`)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *rawURL == "" {
+		return errors.New("missing -url")
+	}
+
+	first := *rawURL
+	if *pageSize > 0 {
+		var err error
+		first, err = iter.SetQuery(first, url.Values{*sizeParam: {strconv.Itoa(*pageSize)}})
+		if err != nil {
+			return err
+		}
+	}
+
+	stats := new(iter.HTTPStats)
+	config := iter.HTTPConfig[map[string]any]{
+		HTTP: iter.HTTP{
+			UserAgent: *userAgent,
+			Header:    http.Header(header),
+		},
+		URL:   first,
+		Stats: stats,
+	}
+	if *tokenField != "" {
+		// the token replaces the one in the URL of the current page
+		var current string
+		config.Prepare = func(req *http.Request) error {
+			current = req.URL.String()
+			return nil
+		}
+		config.Next = func(header http.Header, page map[string]any) string {
+			token, _ := lookup(page, *tokenField).(string)
+			if token == "" {
+				return ""
+			}
+			next, err := iter.SetQuery(current, url.Values{*tokenParam: {token}})
+			if err != nil {
+				return ""
+			}
+			return next
+		}
+	}
+	cursor := iter.NewAutoHTTP(config)
+
+	var (
+		encoder = json.NewEncoder(stdout)
+		report  = iter.ProgressWriter(stderr)
+		state   iter.Progress
+		started = time.Now()
+	)
+	err := cursor.Iterate(func(page map[string]any) error {
+		values := []any{page}
+		if *items != "" {
+			values, _ = lookup(page, *items).([]any)
+		}
+		for _, value := range values {
+			if err := encoder.Encode(value); err != nil {
+				return err
+			}
+		}
+
+		state.Pages++
+		state.Items += len(values)
+		state.Done = !cursor.Next() || state.Pages == *maxPages
+		if *progress {
+			state.Bytes = stats.BytesOnWire.Load()
+			state.Elapsed = time.Since(started)
+			report(state)
+		}
+		if state.Pages == *maxPages {
+			return iter.ErrStop
+		}
+		return nil
+	})
+	return err
+}
+
+// lookup returns value at dot separated path of nested objects.
+func lookup(value any, path string) any {
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"go.teddydd.me/iter/itertest"
+)
+
+func TestRun(t *testing.T) {
+	server := itertest.NewServer(itertest.ServerConfig{Records: 25, Style: itertest.PageToken})
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		args  []string
+		lines int
+	}{
+		{"token field", []string{"-token-field", "next_page_token", "-items", "records", "-page-size", "4"}, 25},
+		{"detected", []string{"-items", "records", "-page-size", "10"}, 25},
+		{"max pages", []string{"-items", "records", "-page-size", "10", "-max-pages", "2"}, 20},
+		{"whole pages", []string{"-page-size", "10"}, 3},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr strings.Builder
+			args := append([]string{"-url", server.URL, "-progress"}, tc.args...)
+			if err := run(args, &stdout, &stderr); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var lines int
+			scanner := bufio.NewScanner(strings.NewReader(stdout.String()))
+			for scanner.Scan() {
+				lines++
+			}
+			if lines != tc.lines {
+				t.Errorf("expected %d lines, got %d:\n%s", tc.lines, lines, stdout.String())
+			}
+			if !strings.HasSuffix(stderr.String(), "\n") || !strings.Contains(stderr.String(), "items") {
+				t.Errorf("unexpected progress: %q", stderr.String())
+			}
+		})
+	}
+
+	if err := run(nil, &strings.Builder{}, &strings.Builder{}); err == nil {
+		t.Error("expected error without -url")
+	}
+}
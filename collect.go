@@ -0,0 +1,41 @@
+package iter
+
+import "errors"
+
+// Collect fetches all remaining Results. If fetching fails, Results
+// fetched before the failure are returned along with the error, so
+// batch jobs can persist partial progress.
+func (d *Cursor[Input, Result]) Collect() ([]Result, error) {
+	return collect(d.Next, d.Get, -1)
+}
+
+// CollectN works like Collect but fetches at most n Results.
+func (d *Cursor[Input, Result]) CollectN(n int) ([]Result, error) {
+	return collect(d.Next, d.Get, n)
+}
+
+// Collect returns all remaining values. If the stream fails, values
+// returned before the failure are returned along with the error.
+func (s *Stream[T]) Collect() ([]T, error) {
+	return collect(s.Next, s.Get, -1)
+}
+
+// CollectN works like Collect but returns at most n values.
+func (s *Stream[T]) CollectN(n int) ([]T, error) {
+	return collect(s.Next, s.Get, n)
+}
+
+func collect[T any](next func() bool, get func() (T, error), n int) ([]T, error) {
+	var values []T
+	for (n < 0 || len(values) < n) && next() {
+		value, err := get()
+		if errors.Is(err, ErrStop) {
+			break
+		}
+		if err != nil {
+			return values, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
@@ -0,0 +1,61 @@
+package iter_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestCollect(t *testing.T) {
+	mockServer := httptest.NewServer(MockAPIHandler(5))
+	defer mockServer.Close()
+
+	iterator := simpleIterator(mockServer)
+
+	pages, err := iterator.CollectN(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(pages, [][]Record{{{1}, {2}}, {{3}, {4}}}) {
+		t.Errorf("unexpected pages: %+v", pages)
+	}
+
+	pages, err = iterator.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(pages, [][]Record{{{5}}, {}}) {
+		t.Errorf("unexpected pages: %+v", pages)
+	}
+}
+
+func TestCollectPartial(t *testing.T) {
+	failure := errors.New("failure")
+	config := pagesConfig([][]int{{1, 2}, {3}, {4}})
+	fetchNext := config.FetchNext
+	config.FetchNext = func(input int) ([]int, error) {
+		if input == 2 {
+			return nil, failure
+		}
+		return fetchNext(input)
+	}
+
+	pages, err := iter.New(config).Collect()
+	if !errors.Is(err, failure) {
+		t.Errorf("expected failure, got %v", err)
+	}
+	if !reflect.DeepEqual(pages, [][]int{{1, 2}, {3}}) {
+		t.Errorf("expected partial pages, got %+v", pages)
+	}
+
+	items, err := iter.Items(iter.New(config)).Collect()
+	if !errors.Is(err, failure) {
+		t.Errorf("expected failure, got %v", err)
+	}
+	if !reflect.DeepEqual(items, []int{1, 2, 3}) {
+		t.Errorf("expected partial items, got %+v", items)
+	}
+}
@@ -0,0 +1,135 @@
+package iter
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Decompressor reads a body compressed with some content coding.
+type Decompressor func(r io.Reader) (io.ReadCloser, error)
+
+// ErrUnsupportedEncoding is returned when there is no decompressor for
+// the Content-Encoding of a response.
+var ErrUnsupportedEncoding = errors.New("unsupported content encoding")
+
+// HTTPStats counts traffic of HTTP adapters. It is safe for concurrent
+// use.
+type HTTPStats struct {
+	// Requests is the number of responses received.
+	Requests atomic.Int64
+	// BytesOnWire is the number of body bytes as transferred.
+	BytesOnWire atomic.Int64
+	// BytesDecoded is the number of body bytes after decompression.
+	BytesDecoded atomic.Int64
+}
+
+var decompressors = map[string]Decompressor{
+	"gzip": func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r)
+	},
+	"deflate": zlib.NewReader,
+}
+
+// acceptEncoding returns value of the Accept-Encoding header for gzip,
+// deflate and extra decompressors.
+func acceptEncoding(extra map[string]Decompressor) string {
+	codings := []string{"gzip", "deflate"}
+	for coding := range extra {
+		if _, ok := decompressors[coding]; !ok {
+			codings = append(codings, coding)
+		}
+	}
+	sort.Strings(codings[2:])
+	return strings.Join(codings, ", ")
+}
+
+// decompress returns the body of the response decoded according to its
+// Content-Encoding and a function releasing the decompressors. Bytes
+// read are counted in stats if not nil. Reading more than limit decoded
+// bytes fails with ErrResponseTooLarge, unless limit is zero.
+func decompress(resp *http.Response, extra map[string]Decompressor, stats *HTTPStats, limit int64) (io.Reader, func(), error) {
+	var closers []io.Closer
+	release := func() {
+		for _, closer := range closers {
+			closer.Close()
+		}
+	}
+
+	var body io.Reader = resp.Body
+	if stats != nil {
+		stats.Requests.Add(1)
+		body = countingReader{body, &stats.BytesOnWire}
+	}
+
+	codings := strings.Split(resp.Header.Get("Content-Encoding"), ",")
+	for i := len(codings) - 1; i >= 0; i-- {
+		coding := strings.ToLower(strings.TrimSpace(codings[i]))
+		if coding == "" || coding == "identity" {
+			continue
+		}
+
+		decompressor, ok := extra[coding]
+		if !ok {
+			decompressor, ok = decompressors[coding]
+		}
+		if !ok {
+			release()
+			return nil, nil, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, coding)
+		}
+
+		reader, err := decompressor(body)
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+		closers = append(closers, reader)
+		body = reader
+	}
+
+	if stats != nil {
+		body = countingReader{body, &stats.BytesDecoded}
+	}
+	if limit > 0 {
+		body = &limitedReader{Reader: body, limit: limit}
+	}
+	return body, release, nil
+}
+
+// limitedReader fails reads past limit bytes with ErrResponseTooLarge.
+type limitedReader struct {
+	io.Reader
+	read  int64
+	limit int64
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	// one byte over the limit is read to tell a body of exactly limit
+	// bytes from a larger one
+	if left := r.limit - r.read + 1; int64(len(p)) > left {
+		p = p[:left]
+	}
+	n, err := r.Reader.Read(p)
+	r.read += int64(n)
+	if r.read > r.limit {
+		return n - int(r.read-r.limit), fmt.Errorf("%w: over %d decoded bytes", ErrResponseTooLarge, r.limit)
+	}
+	return n, err
+}
+
+type countingReader struct {
+	io.Reader
+	count *atomic.Int64
+}
+
+func (r countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.count.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,126 @@
+package iter_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func gzipped(t *testing.T, s string) []byte {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func TestNewHTTPCompression(t *testing.T) {
+	page := `{"numbers": [` + strings.Repeat("1, ", 1000) + `1]}`
+	compressed := gzipped(t, page)
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/gzip":
+			if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+				t.Errorf("gzip not accepted: %q", r.Header.Get("Accept-Encoding"))
+			}
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Header().Set("Link", `</custom>; rel="next"`)
+		case "/custom":
+			if !strings.Contains(r.Header.Get("Accept-Encoding"), "x-gzip2") {
+				t.Errorf("custom coding not accepted: %q", r.Header.Get("Accept-Encoding"))
+			}
+			w.Header().Set("Content-Encoding", "x-gzip2")
+		}
+		w.Write(compressed)
+	}))
+	defer mockServer.Close()
+
+	stats := new(iter.HTTPStats)
+	cursor := iter.NewHTTP(iter.HTTPConfig[numberPage]{
+		URL: mockServer.URL + "/gzip",
+		Decompressors: map[string]iter.Decompressor{
+			"x-gzip2": func(r io.Reader) (io.ReadCloser, error) {
+				return gzip.NewReader(r)
+			},
+		},
+		Stats: stats,
+	})
+
+	var pages int
+	err := cursor.Iterate(func(page numberPage) error {
+		if len(page.Numbers) != 1001 {
+			t.Errorf("unexpected numbers: %d", len(page.Numbers))
+		}
+		pages++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pages != 2 {
+		t.Errorf("unexpected pages: %d", pages)
+	}
+
+	if got := stats.Requests.Load(); got != 2 {
+		t.Errorf("unexpected requests: %d", got)
+	}
+	if got := stats.BytesOnWire.Load(); got != int64(2*len(compressed)) {
+		t.Errorf("unexpected bytes on wire: %d", got)
+	}
+	if got := stats.BytesDecoded.Load(); got != int64(2*len(page)) {
+		t.Errorf("unexpected bytes decoded: %d", got)
+	}
+}
+
+func TestNewHTTPUnsupportedEncoding(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "zstd")
+		w.Write([]byte("not really"))
+	}))
+	defer mockServer.Close()
+
+	cursor := iter.NewHTTP(iter.HTTPConfig[numberPage]{URL: mockServer.URL})
+	err := cursor.Iterate(func(page numberPage) error { return nil })
+	if !errors.Is(err, iter.ErrUnsupportedEncoding) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestHTTPMaxDecodedBytes(t *testing.T) {
+	compressed := gzipped(t, `{"numbers": [`+strings.Repeat("0, ", 100000)+`0]}`)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compressed)
+	}))
+	defer mockServer.Close()
+
+	cursor := iter.NewHTTP(iter.HTTPConfig[numberPage]{
+		HTTP: iter.HTTP{MaxResponseBytes: 10000},
+		URL:  mockServer.URL,
+	})
+	if _, err := cursor.Get(); !errors.Is(err, iter.ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+
+	cursor = iter.NewHTTP(iter.HTTPConfig[numberPage]{
+		HTTP: iter.HTTP{MaxResponseBytes: 10000, MaxDecodedBytes: 1 << 20},
+		URL:  mockServer.URL,
+	})
+	if page, err := cursor.Get(); err != nil || len(page.Numbers) != 100001 {
+		t.Errorf("unexpected page within decoded limit: %d numbers, %v", len(page.Numbers), err)
+	}
+}
@@ -0,0 +1,124 @@
+package iter
+
+import (
+	"sync"
+	"time"
+)
+
+// AIMD adapts concurrency with additive increase and multiplicative
+// decrease: every success raises the limit by 1/limit, so the limit
+// grows by one per round of calls, while errors and calls slower than
+// the target latency halve it. The limit is halved at most once per
+// latency, or per second if latency is zero, so a burst of failures of
+// calls started under the old limit halves it once. It is safe for
+// concurrent use.
+type AIMD struct {
+	mu        sync.Mutex
+	min       float64
+	max       float64
+	latency   time.Duration
+	limit     float64
+	decreased time.Time
+	clock     Clock
+}
+
+// NewAIMD creates a controller keeping the limit between min and max,
+// starting at min. Calls slower than latency count as congestion, zero
+// latency only reacts to errors. Clock is used to time calls,
+// SystemClock if nil.
+func NewAIMD(clock Clock, min, max int, latency time.Duration) *AIMD {
+	if clock == nil {
+		clock = SystemClock
+	}
+	if min < 1 {
+		min = 1
+	}
+	if max < min {
+		max = min
+	}
+	return &AIMD{
+		min:     float64(min),
+		max:     float64(max),
+		latency: latency,
+		limit:   float64(min),
+		clock:   clock,
+	}
+}
+
+// Limit returns the current concurrency limit.
+func (c *AIMD) Limit() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return int(c.limit)
+}
+
+// Observe adjusts the limit by the outcome of a call.
+func (c *AIMD) Observe(latency time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil || (c.latency > 0 && latency > c.latency) {
+		window := c.latency
+		if window == 0 {
+			window = time.Second
+		}
+		now := c.clock.Now()
+		if !c.decreased.IsZero() && now.Sub(c.decreased) < window {
+			return
+		}
+		c.decreased = now
+
+		c.limit /= 2
+		if c.limit < c.min {
+			c.limit = c.min
+		}
+		return
+	}
+
+	c.limit += 1 / c.limit
+	if c.limit > c.max {
+		c.limit = c.max
+	}
+}
+
+// MapAdaptive works like [Map] but the number of concurrent calls of f
+// follows the limit of the controller, which observes every call.
+func MapAdaptive[T, U any](stream *Stream[T], controller *AIMD, f func(value T) (U, error)) *Stream[U] {
+	return mapStream(stream, controller.Limit, func(value T) (U, error) {
+		start := controller.clock.Now()
+		mapped, err := f(value)
+		controller.Observe(controller.clock.Now().Sub(start), err)
+		return mapped, err
+	})
+}
+
+// gate limits the number of concurrent calls to a limit that may
+// change between calls.
+type gate struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	limit   func() int
+	running int
+}
+
+func newGate(limit func() int) *gate {
+	g := &gate{limit: limit}
+	g.cond = sync.NewCond(&g.mu)
+	return g
+}
+
+func (g *gate) acquire() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for g.running >= g.limit() {
+		g.cond.Wait()
+	}
+	g.running++
+}
+
+func (g *gate) release() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.running--
+	g.cond.Broadcast()
+}
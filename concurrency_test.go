@@ -0,0 +1,104 @@
+package iter_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.teddydd.me/iter"
+	"go.teddydd.me/iter/itertest"
+)
+
+func TestAIMD(t *testing.T) {
+	controller := iter.NewAIMD(nil, 1, 3, time.Second)
+
+	limits := []int{controller.Limit()}
+	observe := func(latency time.Duration, err error) {
+		controller.Observe(latency, err)
+		limits = append(limits, controller.Limit())
+	}
+	observe(0, nil)
+	observe(0, nil)
+	observe(0, nil)
+	observe(0, nil)
+	observe(0, nil)
+	observe(2*time.Second, nil)
+	observe(0, errors.New("throttled"))
+
+	expected := []int{1, 2, 2, 2, 3, 3, 1, 1}
+	for i := range expected {
+		if limits[i] != expected[i] {
+			t.Fatalf("unexpected limits: got %v, want %v", limits, expected)
+		}
+	}
+}
+
+func TestAIMDWindow(t *testing.T) {
+	clock := itertest.NewClock(time.Now())
+	controller := iter.NewAIMD(clock, 1, 8, time.Second)
+	for controller.Limit() < 4 {
+		controller.Observe(0, nil)
+	}
+
+	throttled := errors.New("throttled")
+	controller.Observe(0, throttled)
+	controller.Observe(0, throttled)
+	if limit := controller.Limit(); limit != 2 {
+		t.Errorf("expected one decrease per window, got limit %d", limit)
+	}
+
+	clock.Advance(time.Second)
+	controller.Observe(0, throttled)
+	if limit := controller.Limit(); limit != 1 {
+		t.Errorf("expected decrease in the next window, got limit %d", limit)
+	}
+}
+
+func TestMapAdaptive(t *testing.T) {
+	throttled := errors.New("throttled")
+	var running, peak int32
+
+	controller := iter.NewAIMD(nil, 1, 8, 0)
+	stream := iter.MapAdaptive(numbers(100, 10), controller, func(n int) (int, error) {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if current <= p || atomic.CompareAndSwapInt32(&peak, p, current) {
+				break
+			}
+		}
+
+		time.Sleep(time.Millisecond)
+		if current > 4 {
+			return 0, throttled
+		}
+		return n, nil
+	})
+
+	var values, failures int
+	previous := -1
+	for stream.Next() {
+		n, err := stream.Get()
+		if errors.Is(err, iter.ErrStop) {
+			break
+		}
+		if err != nil {
+			failures++
+			continue
+		}
+		if n <= previous {
+			t.Fatalf("value %d after %d", n, previous)
+		}
+		previous = n
+		values++
+	}
+
+	if values+failures != 100 {
+		t.Errorf("expected 100 results, got %d values and %d failures", values, failures)
+	}
+	if p := atomic.LoadInt32(&peak); p < 2 || p > 8 {
+		t.Errorf("unexpected peak concurrency: %d", p)
+	}
+}
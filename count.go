@@ -0,0 +1,62 @@
+package iter
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCountMismatch is returned by [CheckCount] when the number of
+// fetched items differs from the total reported by the API.
+var ErrCountMismatch = errors.New("fetched items do not match reported total")
+
+// CheckCount returns middleware comparing the number of fetched items
+// to the total reported in results, as a cheap end-to-end sanity check
+// of backfills. Total returns the expected number of items, zero if
+// the result does not report it; the last reported value is used.
+// Items are counted once HasNext accepts a page, so pages fetched again
+// are not counted twice. If HasNext reports the last page and the
+// counts differ by more than tolerance, the cursor is not depleted and
+// the following Get fails with ErrCountMismatch without fetching.
+func CheckCount[Input, T any](total func(result []T) int, tolerance int) Middleware[Input, []T] {
+	return func(config Config[Input, []T]) Config[Input, []T] {
+		var (
+			fetched, reported int
+			mismatch          error
+		)
+
+		getFirstInput := config.GetFirstInput
+		config.GetFirstInput = func() Input {
+			fetched, reported, mismatch = 0, 0, nil
+			return getFirstInput()
+		}
+
+		config.wrapFetch(func(fetchNext func(input Input) ([]T, error)) func(input Input) ([]T, error) {
+			return func(input Input) ([]T, error) {
+				if mismatch != nil {
+					return nil, mismatch
+				}
+				return fetchNext(input)
+			}
+		})
+
+		hasNext := config.HasNext
+		config.HasNext = func(result []T) (Input, bool) {
+			input, ok := hasNext(result)
+
+			fetched += len(result)
+			if t := total(result); t > 0 {
+				reported = t
+			}
+			if ok || reported == 0 {
+				return input, ok
+			}
+			if diff := fetched - reported; diff > tolerance || -diff > tolerance {
+				mismatch = fmt.Errorf("%w: fetched %d, reported %d", ErrCountMismatch, fetched, reported)
+				return input, true
+			}
+			return input, false
+		}
+
+		return config
+	}
+}
@@ -0,0 +1,58 @@
+package iter_test
+
+import (
+	"errors"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestCheckCount(t *testing.T) {
+	total := func(result []int) int { return 5 }
+
+	for _, tc := range []struct {
+		name      string
+		pages     [][]int
+		tolerance int
+		err       error
+	}{
+		{name: "match", pages: [][]int{{1, 2}, {3, 4, 5}}},
+		{name: "missing", pages: [][]int{{1, 2}, {3, 4}}, err: iter.ErrCountMismatch},
+		{name: "tolerated", pages: [][]int{{1, 2}, {3, 4}}, tolerance: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config := iter.Use(pagesConfig(tc.pages), iter.CheckCount[int](total, tc.tolerance))
+			pages, err := iter.New(config).Collect()
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected %v, got %v", tc.err, err)
+			}
+			if len(pages) != len(tc.pages) {
+				t.Errorf("expected all pages, got %v", pages)
+			}
+
+			// retried pages are counted once
+			config = iter.Use(pagesConfig(tc.pages), iter.CheckCount[int](total, tc.tolerance))
+			if _, err := collectRetrying(iter.New(config)); !errors.Is(err, tc.err) {
+				t.Errorf("expected %v with retries, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestCheckCountHasNextOnce(t *testing.T) {
+	var calls int
+	config := pagesConfig([][]int{{1}, {2}})
+	hasNext := config.HasNext
+	config.HasNext = func(result []int) (int, bool) {
+		calls++
+		return hasNext(result)
+	}
+
+	cursor := iter.New(iter.Use(config, iter.CheckCount[int](func([]int) int { return 2 }, 0)))
+	if _, err := cursor.Collect(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("expected HasNext to be called once per page, got %d calls", calls)
+	}
+}
@@ -0,0 +1,41 @@
+package iter
+
+import "errors"
+
+// IterateDeadLetter works like [Cursor.Iterate] but pages for which
+// callback fails are passed to deadLetter along with the failure reason
+// instead of aborting the iteration. Iteration is aborted only if
+// deadLetter returns an error.
+func (d *Cursor[Input, Result]) IterateDeadLetter(
+	callback func(response Result) error,
+	deadLetter func(response Result, reason error) error,
+) error {
+	return iterateDeadLetter(d.Next, d.Get, callback, deadLetter)
+}
+
+// IterateDeadLetter works like [Stream.Iterate] but values for which
+// callback fails are passed to deadLetter along with the failure reason
+// instead of aborting the iteration. Iteration is aborted only if
+// deadLetter returns an error.
+func (s *Stream[T]) IterateDeadLetter(
+	callback func(value T) error,
+	deadLetter func(value T, reason error) error,
+) error {
+	return iterateDeadLetter(s.Next, s.Get, callback, deadLetter)
+}
+
+func iterateDeadLetter[T any](
+	next func() bool,
+	get func() (T, error),
+	callback func(value T) error,
+	deadLetter func(value T, reason error) error,
+) error {
+	return iterate(next, get, func(value T) error {
+		err := callback(value)
+		if err == nil || errors.Is(err, ErrStop) {
+			return err
+		}
+
+		return deadLetter(value, err)
+	})
+}
@@ -0,0 +1,69 @@
+package iter_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestIterateDeadLetter(t *testing.T) {
+	mockServer := httptest.NewServer(MockAPIHandler(5))
+	defer mockServer.Close()
+
+	errOdd := errors.New("odd record")
+	process := func(record Record) error {
+		if record.ID%2 == 1 {
+			return errOdd
+		}
+		return nil
+	}
+
+	t.Run("items", func(t *testing.T) {
+		stream := iter.Items(simpleIterator(mockServer))
+
+		var processed, dead []Record
+		err := stream.IterateDeadLetter(func(record Record) error {
+			if err := process(record); err != nil {
+				return err
+			}
+			processed = append(processed, record)
+			return nil
+		}, func(record Record, reason error) error {
+			if !errors.Is(reason, errOdd) {
+				t.Errorf("unexpected reason: %+v", reason)
+			}
+			dead = append(dead, record)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if !reflect.DeepEqual(processed, []Record{{2}, {4}}) {
+			t.Errorf("unexpected processed records: %+v", processed)
+		}
+		if !reflect.DeepEqual(dead, []Record{{1}, {3}, {5}}) {
+			t.Errorf("unexpected dead letters: %+v", dead)
+		}
+	})
+
+	t.Run("dead letter failure aborts", func(t *testing.T) {
+		iterator := simpleIterator(mockServer)
+		errSink := errors.New("sink unavailable")
+		pages := 0
+		err := iterator.IterateDeadLetter(func(response []Record) error {
+			pages++
+			return errOdd
+		}, func(response []Record, reason error) error {
+			return errSink
+		})
+		if !errors.Is(err, errSink) {
+			t.Fatalf("expected sink error, got %+v", err)
+		}
+		if pages != 1 {
+			t.Errorf("expected iteration to abort after first page, got %d pages", pages)
+		}
+	})
+}
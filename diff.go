@@ -0,0 +1,113 @@
+package iter
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DiffKind is the kind of a [Difference].
+type DiffKind int
+
+const (
+	// Added values are only in the second stream.
+	Added DiffKind = iota + 1
+	// Removed values are only in the first stream.
+	Removed
+	// Changed values are in both streams but differ.
+	Changed
+)
+
+func (k DiffKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	}
+	return fmt.Sprintf("DiffKind(%d)", int(k))
+}
+
+// Difference between two streams. Old is the value from the first
+// stream and New the value from the second one, the missing one is
+// zero.
+type Difference[T any] struct {
+	Kind     DiffKind
+	Old, New T
+}
+
+// Diff returns stream of differences between streams a and b, e.g. to
+// validate API migrations and backfills. Both streams must be sorted by
+// key in ascending order according to compare. Values with equal keys
+// are compared with equal.
+func Diff[T, K any](
+	a, b *Stream[T],
+	key func(value T) K,
+	compare func(a, b K) int,
+	equal func(a, b T) bool,
+) *Stream[Difference[T]] {
+	var (
+		oldValue, newValue T
+		hasOld, hasNew     bool
+		oldDone            bool
+		newDone            bool
+	)
+
+	load := func(stream *Stream[T], value *T, has, done *bool) error {
+		if *has || *done {
+			return nil
+		}
+		v, err := stream.Get()
+		if errors.Is(err, ErrStop) {
+			*done = true
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		*value, *has = v, true
+		return nil
+	}
+
+	return newStream(func() (Difference[T], error) {
+		for {
+			if err := load(a, &oldValue, &hasOld, &oldDone); err != nil {
+				return Difference[T]{}, err
+			}
+			if err := load(b, &newValue, &hasNew, &newDone); err != nil {
+				return Difference[T]{}, err
+			}
+
+			var c int
+			switch {
+			case !hasOld && !hasNew:
+				return Difference[T]{}, ErrStop
+			case !hasOld:
+				c = 1
+			case !hasNew:
+				c = -1
+			default:
+				c = compare(key(oldValue), key(newValue))
+			}
+
+			switch {
+			case c < 0:
+				hasOld = false
+				return Difference[T]{Kind: Removed, Old: oldValue}, nil
+			case c > 0:
+				hasNew = false
+				return Difference[T]{Kind: Added, New: newValue}, nil
+			}
+
+			hasOld, hasNew = false, false
+			if !equal(oldValue, newValue) {
+				return Difference[T]{Kind: Changed, Old: oldValue, New: newValue}, nil
+			}
+		}
+	}, func() {
+		a.Reset()
+		b.Reset()
+		hasOld, hasNew, oldDone, newDone = false, false, false, false
+	})
+}
@@ -0,0 +1,48 @@
+package iter_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestDiff(t *testing.T) {
+	before := iter.Items(iter.New(pagesConfig([][]user{
+		{{1, "ann"}, {2, "bob"}},
+		{{3, "cid"}, {5, "dan"}},
+	})))
+	after := iter.Items(iter.New(pagesConfig([][]user{
+		{{1, "ann"}},
+		{{3, "cyd"}, {4, "eve"}, {5, "dan"}, {6, "fay"}},
+	})))
+
+	stream := iter.Diff(before, after, func(u user) int {
+		return u.ID
+	}, func(a, b int) int {
+		return a - b
+	}, func(a, b user) bool {
+		return a == b
+	})
+
+	var changes []string
+	for _, d := range collect(t, stream) {
+		changes = append(changes, fmt.Sprintf("%s %v %v", d.Kind, d.Old, d.New))
+	}
+
+	expected := []string{
+		"removed {2 bob} {0 }",
+		"changed {3 cid} {3 cyd}",
+		"added {0 } {4 eve}",
+		"added {0 } {6 fay}",
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("unexpected changes: %q", changes)
+	}
+
+	stream.Reset()
+	if len(collect(t, stream)) != 4 {
+		t.Errorf("unexpected changes after reset")
+	}
+}
@@ -0,0 +1,25 @@
+package iter_test
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestCountDistinct(t *testing.T) {
+	for _, distinct := range []int{0, 10, 1000, 100000} {
+		// every value is repeated twice
+		count, err := iter.CountDistinct(numbers(2*distinct, 1000), 14, func(n int) string {
+			return strconv.Itoa(n % distinct)
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if diff := math.Abs(float64(count) - float64(distinct)); diff > 0.03*float64(distinct)+1 {
+			t.Errorf("estimated %d distinct values, expected about %d", count, distinct)
+		}
+	}
+}
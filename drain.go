@@ -0,0 +1,44 @@
+package iter
+
+import "context"
+
+// BatchSink consumes values in batches, e.g. bulk inserts them into a
+// database. Flush is called once after the last batch.
+type BatchSink[T any] interface {
+	WriteBatch(ctx context.Context, values []T) error
+	Flush(ctx context.Context) error
+}
+
+// Drain writes all values of the stream to sink in batches of up to
+// batchSize values and flushes it. The first error of the stream or
+// the sink, or cancellation of ctx, aborts draining; the sink is not
+// flushed then.
+func Drain[T any](ctx context.Context, stream *Stream[T], sink BatchSink[T], batchSize int) error {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	batch := make([]T, 0, batchSize)
+	err := stream.Iterate(func(value T) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch = append(batch, value)
+		if len(batch) < batchSize {
+			return nil
+		}
+		err := sink.WriteBatch(ctx, batch)
+		batch = make([]T, 0, batchSize)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(batch) > 0 {
+		if err := sink.WriteBatch(ctx, batch); err != nil {
+			return err
+		}
+	}
+	return sink.Flush(ctx)
+}
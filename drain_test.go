@@ -0,0 +1,69 @@
+package iter_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+type batchRecorder struct {
+	batches [][]int
+	flushed bool
+	fail    error
+}
+
+func (r *batchRecorder) WriteBatch(ctx context.Context, values []int) error {
+	r.batches = append(r.batches, values)
+	return r.fail
+}
+
+func (r *batchRecorder) Flush(ctx context.Context) error {
+	r.flushed = true
+	return nil
+}
+
+func TestDrain(t *testing.T) {
+	pages := [][]int{{1, 2}, {3, 4, 5}}
+
+	t.Run("batches", func(t *testing.T) {
+		sink := &batchRecorder{}
+		err := iter.Drain[int](context.Background(), iter.Items(iter.New(pagesConfig(pages))), sink, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := [][]int{{1, 2}, {3, 4}, {5}}; !reflect.DeepEqual(sink.batches, expected) {
+			t.Errorf("unexpected batches: %v", sink.batches)
+		}
+		if !sink.flushed {
+			t.Error("sink not flushed")
+		}
+	})
+
+	t.Run("sink error", func(t *testing.T) {
+		fail := errors.New("fail")
+		sink := &batchRecorder{fail: fail}
+		err := iter.Drain[int](context.Background(), iter.Items(iter.New(pagesConfig(pages))), sink, 2)
+		if !errors.Is(err, fail) {
+			t.Errorf("expected sink error, got %v", err)
+		}
+		if len(sink.batches) != 1 || sink.flushed {
+			t.Errorf("draining not aborted: %v, flushed %v", sink.batches, sink.flushed)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		sink := &batchRecorder{}
+		err := iter.Drain[int](ctx, iter.Items(iter.New(pagesConfig(pages))), sink, 2)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected cancellation, got %v", err)
+		}
+		if sink.batches != nil || sink.flushed {
+			t.Errorf("unexpected writes: %v, flushed %v", sink.batches, sink.flushed)
+		}
+	})
+}
@@ -0,0 +1,24 @@
+package iter
+
+// DryRunReport summarizes what a job would touch.
+type DryRunReport struct {
+	Pages int
+	Items int
+}
+
+// DryRun fetches all pages of the cursor without processing them, to
+// preview how many pages and items a job would touch before running it
+// for real. Items returns the number of items in the page; it may be
+// nil to count only pages. The cursor should be reset before the real
+// run.
+func (d *Cursor[Input, Result]) DryRun(items func(response Result) int) (DryRunReport, error) {
+	var report DryRunReport
+	err := d.Iterate(func(response Result) error {
+		report.Pages++
+		if items != nil {
+			report.Items += items(response)
+		}
+		return nil
+	})
+	return report, err
+}
@@ -0,0 +1,25 @@
+package iter_test
+
+import (
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestDryRun(t *testing.T) {
+	cursor := iter.New(pagesConfig([][]int{{1, 2}, {3}, {4, 5, 6}}))
+
+	report, err := cursor.DryRun(func(page []int) int { return len(page) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report != (iter.DryRunReport{Pages: 3, Items: 6}) {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	cursor.Reset()
+	values, err := iter.Items(cursor).Collect()
+	if err != nil || len(values) != 6 {
+		t.Errorf("unexpected real run: %v, %v", values, err)
+	}
+}
@@ -0,0 +1,49 @@
+package iter
+
+// KeyRangeConfig configures paging through a range of an etcd keyspace.
+type KeyRangeConfig[KV any] struct {
+	// Start is the first key of the range.
+	Start string
+	// End is the exclusive end of the range (etcd range_end).
+	End string
+	// Limit is the maximum number of keys in a page.
+	Limit int64
+	// Get should fetch at most limit keys in range [key, end), e.g.
+	// with clientv3:
+	//
+	//	resp, err := cli.Get(ctx, key, clientv3.WithRange(end), clientv3.WithLimit(limit))
+	//	if err != nil {
+	//		return nil, false, err
+	//	}
+	//	return resp.Kvs, resp.More, nil
+	Get func(key, end string, limit int64) (kvs []KV, more bool, err error)
+	// Key returns the key of the pair, e.g. string(kv.Key).
+	Key func(kv KV) string
+}
+
+// NewKeyRange creates a cursor paging through an etcd key range. Each
+// page starts right after the last key of the previous one, which is
+// used as input, so backups and scans of large keyspaces can be done
+// incrementally.
+func NewKeyRange[KV any](config KeyRangeConfig[KV]) *Cursor[string, Page[string, KV]] {
+	return New(Config[string, Page[string, KV]]{
+		HasNext: func(result Page[string, KV]) (string, bool) {
+			return result.Next, result.Next != ""
+		},
+		FetchNext: func(input string) (Page[string, KV], error) {
+			kvs, more, err := config.Get(input, config.End, config.Limit)
+			if err != nil {
+				return Page[string, KV]{}, err
+			}
+
+			page := Page[string, KV]{Items: kvs}
+			if more && len(kvs) > 0 {
+				page.Next = config.Key(kvs[len(kvs)-1]) + "\x00"
+			}
+			return page, nil
+		},
+		GetFirstInput: func() string {
+			return config.Start
+		},
+	})
+}
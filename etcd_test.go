@@ -0,0 +1,52 @@
+package iter_test
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestNewKeyRange(t *testing.T) {
+	keyspace := []string{"/a", "/app/1", "/app/2", "/app/2/x", "/app/3", "/b"}
+	sort.Strings(keyspace)
+
+	var starts []string
+	iterator := iter.NewKeyRange(iter.KeyRangeConfig[string]{
+		Start: "/app/",
+		End:   "/app0",
+		Limit: 2,
+		Get: func(key, end string, limit int64) ([]string, bool, error) {
+			starts = append(starts, key)
+			var kvs []string
+			for _, k := range keyspace {
+				if k >= key && k < end {
+					kvs = append(kvs, k)
+				}
+			}
+			if int64(len(kvs)) > limit {
+				return kvs[:limit], true, nil
+			}
+			return kvs, false, nil
+		},
+		Key: func(kv string) string {
+			return kv
+		},
+	})
+
+	var keys []string
+	err := iterator.Iterate(func(response iter.Page[string, string]) error {
+		keys = append(keys, response.Items...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(keys, []string{"/app/1", "/app/2", "/app/2/x", "/app/3"}) {
+		t.Errorf("unexpected keys: %+v", keys)
+	}
+	if !reflect.DeepEqual(starts, []string{"/app/", "/app/2\x00"}) {
+		t.Errorf("unexpected range starts: %q", starts)
+	}
+}
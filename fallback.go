@@ -0,0 +1,29 @@
+package iter
+
+import "errors"
+
+// Fallback returns middleware fetching a page with fetch (mirror
+// endpoint, cache, replica) when the primary fetch fails with an error
+// for which permanent returns true; nil permanent treats every error as
+// permanent. The page fetched by either source is passed to HasNext, so
+// the cursor advances the same way regardless of the source. If both
+// fail, their errors are joined.
+func Fallback[Input, Result any](
+	fetch func(input Input) (Result, error),
+	permanent func(err error) bool,
+) Middleware[Input, Result] {
+	return WrapFetch(func(fetchNext func(input Input) (Result, error)) func(input Input) (Result, error) {
+		return func(input Input) (Result, error) {
+			result, err := fetchNext(input)
+			if err == nil || errors.Is(err, ErrStop) || (permanent != nil && !permanent(err)) {
+				return result, err
+			}
+
+			result, fallbackErr := fetch(input)
+			if fallbackErr != nil {
+				return result, errors.Join(err, fallbackErr)
+			}
+			return result, nil
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package iter_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestFallback(t *testing.T) {
+	var (
+		errGone      = errors.New("gone")
+		errTransient = errors.New("transient")
+		errMirror    = errors.New("mirror down")
+	)
+	pages := [][]int{{1}, {2}, {3}}
+
+	newCursor := func(fail error, mirror error) *iter.Cursor[int, []int] {
+		config := pagesConfig(pages)
+		fetchNext := config.FetchNext
+		config.FetchNext = func(input int) ([]int, error) {
+			if input == 1 {
+				return nil, fail
+			}
+			return fetchNext(input)
+		}
+		return iter.New(iter.Use(config, iter.Fallback(func(input int) ([]int, error) {
+			if mirror != nil {
+				return nil, mirror
+			}
+			return fetchNext(input)
+		}, func(err error) bool {
+			return errors.Is(err, errGone)
+		})))
+	}
+
+	values, err := iter.Items(newCursor(errGone, nil)).Collect()
+	if err != nil || !reflect.DeepEqual(values, []int{1, 2, 3}) {
+		t.Errorf("unexpected values: %v, %v", values, err)
+	}
+
+	if _, err := newCursor(errTransient, nil).Collect(); !errors.Is(err, errTransient) {
+		t.Errorf("expected transient error, got %v", err)
+	}
+
+	_, err = newCursor(errGone, errMirror).Collect()
+	if !errors.Is(err, errGone) || !errors.Is(err, errMirror) {
+		t.Errorf("expected both errors, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package iter
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+)
+
+// FeedConfig configures iteration over paged or archived RSS and Atom
+// feeds (RFC 5005).
+type FeedConfig struct {
+	HTTP
+	// URL of the first feed document.
+	URL string
+	// Rel is the relation of links to follow, "next" by default. Use
+	// "prev-archive" to walk archived feeds.
+	Rel string
+}
+
+// FeedPage is a feed document decoded into Feed.
+type FeedPage[Feed any] struct {
+	URL  string
+	Feed Feed
+	// Next is the URL of the following document, empty for the last one.
+	Next string
+}
+
+type feedLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
+type feedLinks struct {
+	Links   []feedLink `xml:"http://www.w3.org/2005/Atom link"`
+	Channel struct {
+		Links []feedLink `xml:"http://www.w3.org/2005/Atom link"`
+	} `xml:"channel"`
+}
+
+// NewFeed creates a cursor over a paged RSS or Atom feed. Documents are
+// decoded into Feed with encoding/xml and followed by Atom links with
+// the configured relation, also when embedded in RSS as atom:link.
+func NewFeed[Feed any](config FeedConfig) *Cursor[string, FeedPage[Feed]] {
+	rel := config.Rel
+	if rel == "" {
+		rel = "next"
+	}
+
+	return New(Config[string, FeedPage[Feed]]{
+		HasNext: func(result FeedPage[Feed]) (string, bool) {
+			return result.Next, result.Next != ""
+		},
+		FetchNextContext: func(ctx context.Context, input string) (FeedPage[Feed], error) {
+			page := FeedPage[Feed]{URL: input}
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, input, nil)
+			if err != nil {
+				return page, config.redactError(err)
+			}
+			resp, err := config.do(req)
+			if err != nil {
+				return page, err
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				return page, &StatusError{StatusCode: resp.StatusCode, URL: config.redact(input)}
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return page, err
+			}
+			if err := xml.Unmarshal(body, &page.Feed); err != nil {
+				return page, err
+			}
+
+			var links feedLinks
+			if err := xml.Unmarshal(body, &links); err != nil {
+				return page, err
+			}
+			for _, link := range append(links.Links, links.Channel.Links...) {
+				if link.Rel == rel {
+					page.Next, err = ResolveURL(input, link.Href)
+					break
+				}
+			}
+
+			return page, config.redactError(err)
+		},
+		GetFirstInput: func() string {
+			return config.URL
+		},
+		Redact: config.Redact,
+	})
+}
@@ -0,0 +1,74 @@
+package iter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+const (
+	atomPage = `<?xml version="1.0"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <link rel="self" href="/feed"/>
+  <link rel="next" href="/feed?page=2"/>
+  <entry><title>one</title></entry>
+  <entry><title>two</title></entry>
+</feed>`
+	rssPage = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel>
+    <link>http://example.com</link>
+    <atom:link rel="next" href="feed?page=3"/>
+    <item><title>three</title></item>
+  </channel>
+</rss>`
+	lastPage = `<?xml version="1.0"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <link rel="prev" href="/feed?page=2"/>
+  <entry><title>four</title></entry>
+</feed>`
+)
+
+// entries decodes both Atom and RSS documents used in the test.
+type entries struct {
+	Titles []string `xml:"entry>title"`
+	Items  []string `xml:"channel>item>title"`
+}
+
+func TestNewFeed(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Write([]byte(atomPage))
+		case "2":
+			w.Write([]byte(rssPage))
+		case "3":
+			w.Write([]byte(lastPage))
+		}
+	}))
+	defer mockServer.Close()
+
+	iterator := iter.NewFeed[entries](iter.FeedConfig{URL: mockServer.URL + "/feed"})
+
+	var titles, urls []string
+	err := iterator.Iterate(func(response iter.FeedPage[entries]) error {
+		urls = append(urls, response.URL)
+		titles = append(titles, response.Feed.Titles...)
+		titles = append(titles, response.Feed.Items...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(titles, []string{"one", "two", "three", "four"}) {
+		t.Errorf("unexpected titles: %+v", titles)
+	}
+
+	expected := []string{mockServer.URL + "/feed", mockServer.URL + "/feed?page=2", mockServer.URL + "/feed?page=3"}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Errorf("unexpected urls: %+v", urls)
+	}
+}
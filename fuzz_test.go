@@ -0,0 +1,33 @@
+package iter_test
+
+import (
+	"testing"
+
+	"go.teddydd.me/iter"
+	"go.teddydd.me/iter/itertest"
+)
+
+func FuzzCheckpoint(f *testing.F) {
+	iter.RegisterCodec[offset](offsetCodec{})
+
+	f.Add([]byte(`{"input":2,"done":false}`))
+	f.Add([]byte(`{"version":1,"input":{"page":3,"size":10},"done":true}`))
+	f.Add([]byte(`{"input":"NDI=","done":false}`))
+	f.Add([]byte(`{"version":-1,"input":null}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		itertest.CheckpointRoundTrip[int](t, data)
+		itertest.CheckpointRoundTrip[string](t, data)
+		itertest.CheckpointRoundTrip[offset](t, data)
+		itertest.CheckpointRoundTrip[pageInput](t, data)
+	})
+}
+
+func FuzzCodec(f *testing.F) {
+	f.Add([]byte("42"))
+	f.Add([]byte("-0"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		itertest.CodecRoundTrip[offset](t, offsetCodec{}, data)
+	})
+}
@@ -0,0 +1,113 @@
+package iter
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commit is a commit listed by [NewGitLog].
+type Commit struct {
+	Hash    string
+	Author  string
+	Email   string
+	Time    time.Time
+	Subject string
+}
+
+// GitLogConfig configures iteration over git history.
+type GitLogConfig struct {
+	// Dir is the repository directory, the current directory if empty.
+	Dir string
+	// Rev is the revision to start from, HEAD if empty.
+	Rev string
+	// BatchSize is the number of commits in a page, 100 if zero.
+	BatchSize int
+}
+
+// NewGitLog creates a cursor paging through first-parent history of a
+// git repository with the git command. Hash of the last listed commit is
+// used as input, so the iteration can be resumed from it.
+func NewGitLog(config GitLogConfig) *Cursor[string, Page[string, Commit]] {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+
+	return New(Config[string, Page[string, Commit]]{
+		HasNext: func(result Page[string, Commit]) (string, bool) {
+			return result.Next, len(result.Items) == config.BatchSize
+		},
+		FetchNext: func(input string) (Page[string, Commit], error) {
+			rev, count := config.Rev, config.BatchSize
+			if rev == "" {
+				rev = "HEAD"
+			}
+			if input != "" {
+				// list from the last seen commit and skip it
+				rev, count = input, count+1
+			}
+
+			commits, err := gitLog(config.Dir, rev, count)
+			if err != nil {
+				return Page[string, Commit]{Next: input}, err
+			}
+			if input != "" && len(commits) > 0 {
+				commits = commits[1:]
+			}
+
+			page := Page[string, Commit]{Items: commits, Next: input}
+			if len(commits) > 0 {
+				page.Next = commits[len(commits)-1].Hash
+			}
+			return page, nil
+		},
+		GetFirstInput: func() string {
+			return ""
+		},
+	})
+}
+
+func gitLog(dir, rev string, count int) ([]Commit, error) {
+	cmd := exec.Command("git", "log", "--first-parent",
+		"--max-count="+strconv.Itoa(count),
+		"--format=%H%x1f%an%x1f%ae%x1f%at%x1f%s%x1e",
+		rev, "--")
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log %s: %w: %s", rev, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(string(out), "\x1e") {
+		record = strings.TrimPrefix(record, "\n")
+		if record == "" {
+			continue
+		}
+
+		fields := strings.Split(record, "\x1f")
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("git log %s: unexpected output %q", rev, record)
+		}
+		timestamp, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("git log %s: %w", rev, err)
+		}
+
+		commits = append(commits, Commit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Email:   fields[2],
+			Time:    time.Unix(timestamp, 0),
+			Subject: fields[4],
+		})
+	}
+
+	return commits, nil
+}
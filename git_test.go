@@ -0,0 +1,85 @@
+package iter_test
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func gitRepository(t *testing.T, commits int) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	git("init", "-q")
+	for i := 1; i <= commits; i++ {
+		git("commit", "-q", "--allow-empty", "-m", "commit "+strconv.Itoa(i))
+	}
+
+	return dir
+}
+
+func TestNewGitLog(t *testing.T) {
+	dir := gitRepository(t, 5)
+	iterator := iter.NewGitLog(iter.GitLogConfig{Dir: dir, BatchSize: 2})
+
+	var subjects []string
+	var pages int
+	err := iterator.Iterate(func(response iter.Page[string, iter.Commit]) error {
+		pages++
+		for _, commit := range response.Items {
+			if len(commit.Hash) != 40 || commit.Author != "Test" || commit.Email != "test@example.com" {
+				t.Errorf("unexpected commit: %+v", commit)
+			}
+			subjects = append(subjects, commit.Subject)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"commit 5", "commit 4", "commit 3", "commit 2", "commit 1"}
+	if !reflect.DeepEqual(subjects, expected) {
+		t.Errorf("unexpected subjects: %+v", subjects)
+	}
+	if pages != 3 {
+		t.Errorf("expected 3 pages, got %d", pages)
+	}
+
+	t.Run("default batch size", func(t *testing.T) {
+		iterator := iter.NewGitLog(iter.GitLogConfig{Dir: dir})
+		response, err := iterator.Get()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(response.Items) != 5 || iterator.Next() {
+			t.Errorf("expected all commits in one page, got %d", len(response.Items))
+		}
+	})
+
+	t.Run("bad revision", func(t *testing.T) {
+		iterator := iter.NewGitLog(iter.GitLogConfig{Dir: dir, Rev: "does-not-exist", BatchSize: 2})
+		if _, err := iterator.Get(); err == nil {
+			t.Errorf("expected error for bad revision")
+		}
+	})
+}
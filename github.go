@@ -0,0 +1,141 @@
+package iter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// GitHubConfig configures iteration over GitHub REST API listings.
+type GitHubConfig struct {
+	HTTP
+	// URL of the first page, e.g.
+	// https://api.github.com/repos/golang/go/issues?per_page=100
+	URL string
+	// Token is optional access token used to authenticate requests.
+	Token string
+	// MaxRetries limits retries of rate limited requests, 5 if zero.
+	MaxRetries int
+}
+
+// gitHubBackoff is the first delay of retries not limited by headers.
+const gitHubBackoff = time.Second
+
+// NewGitHub creates a cursor over GitHub REST API listing. Pages are
+// followed with the Link header and decoded as JSON arrays of Items, or
+// objects with "items" field as returned by search endpoints.
+//
+// Rate limits are respected: once X-RateLimit-Remaining drops to zero
+// the next request waits until X-RateLimit-Reset, and rate limited
+// requests are retried after Retry-After or the reset time, with
+// exponential backoff if neither is present.
+func NewGitHub[Item any](config GitHubConfig) *Cursor[string, Page[string, Item]] {
+	var resume time.Time
+	clock := config.clock()
+	maxRetries := config.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = 5
+	}
+
+	fetch := func(ctx context.Context, url string) (*http.Response, error) {
+		for attempt := 0; ; attempt++ {
+			clock.Sleep(resume.Sub(clock.Now()))
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return nil, config.redactError(err)
+			}
+			req.Header.Set("Accept", "application/vnd.github+json")
+			if config.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+config.Token)
+			}
+
+			resp, err := config.do(req)
+			if err != nil {
+				return nil, err
+			}
+
+			resume = gitHubResume(resp, clock.Now())
+			limited := resp.StatusCode == http.StatusTooManyRequests ||
+				(resp.StatusCode == http.StatusForbidden && !resume.IsZero())
+			if !limited {
+				return resp, nil
+			}
+			resp.Body.Close()
+
+			if attempt >= maxRetries {
+				return nil, &StatusError{StatusCode: resp.StatusCode, URL: config.redact(url)}
+			}
+			if resume.IsZero() {
+				resume = clock.Now().Add(gitHubBackoff << attempt)
+			}
+		}
+	}
+
+	return New(Config[string, Page[string, Item]]{
+		HasNext: func(result Page[string, Item]) (string, bool) {
+			return result.Next, result.Next != ""
+		},
+		FetchNextContext: func(ctx context.Context, input string) (Page[string, Item], error) {
+			resp, err := fetch(ctx, input)
+			if err != nil {
+				return Page[string, Item]{}, err
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				return Page[string, Item]{}, &StatusError{StatusCode: resp.StatusCode, URL: config.redact(input)}
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return Page[string, Item]{}, err
+			}
+
+			var items []Item
+			if bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+				var search struct {
+					Items []Item `json:"items"`
+				}
+				err = json.Unmarshal(body, &search)
+				items = search.Items
+			} else {
+				err = json.Unmarshal(body, &items)
+			}
+			if err != nil {
+				return Page[string, Item]{}, err
+			}
+
+			return Page[string, Item]{
+				Items: items,
+				Next:  linkTarget(resp.Header, "next"),
+			}, nil
+		},
+		GetFirstInput: func() string {
+			resume = time.Time{}
+			return config.URL
+		},
+		Redact: config.Redact,
+	})
+}
+
+// gitHubResume returns time when next request can be sent, or zero time
+// if it is not limited.
+func gitHubResume(resp *http.Response, now time.Time) time.Time {
+	if delay, ok := retryAfter(resp.Header); ok {
+		return now.Add(delay)
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return time.Time{}
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(reset, 0)
+}
@@ -0,0 +1,170 @@
+package iter_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.teddydd.me/iter"
+	"go.teddydd.me/iter/itertest"
+)
+
+type issue struct {
+	Number int `json:"number"`
+}
+
+func gitHubHandler(t *testing.T, limited *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("unexpected authorization header: %q", r.Header.Get("Authorization"))
+		}
+		if *limited > 0 {
+			*limited--
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		if page < 3 {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/issues?page=%d>; rel="next", <http://%s/issues?page=3>; rel="last"`, r.Host, page+1, r.Host))
+		}
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "0")
+
+		issues := []issue{{Number: page*2 - 1}, {Number: page * 2}}
+		if page == 2 {
+			// search endpoints wrap items in an object
+			json.NewEncoder(w).Encode(map[string]any{"total_count": 6, "items": issues})
+			return
+		}
+		json.NewEncoder(w).Encode(issues)
+	})
+}
+
+func TestNewGitHub(t *testing.T) {
+	limited := 2
+	mockServer := httptest.NewServer(gitHubHandler(t, &limited))
+	defer mockServer.Close()
+
+	iterator := iter.NewGitHub[issue](iter.GitHubConfig{
+		URL:   mockServer.URL + "/issues",
+		Token: "secret",
+	})
+
+	var numbers []int
+	err := iterator.Iterate(func(response iter.Page[string, issue]) error {
+		for _, issue := range response.Items {
+			numbers = append(numbers, issue.Number)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(numbers, []int{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("unexpected issues: %+v", numbers)
+	}
+	if limited != 0 {
+		t.Errorf("expected rate limited requests to be retried")
+	}
+}
+
+func TestNewGitHubStatusError(t *testing.T) {
+	mockServer := httptest.NewServer(brokenServerHandler())
+	defer mockServer.Close()
+
+	iterator := iter.NewGitHub[issue](iter.GitHubConfig{URL: mockServer.URL})
+	_, err := iterator.Get()
+
+	var statusErr *iter.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected status error, got %+v", err)
+	}
+}
+
+func TestNewGitHubRetryAfter(t *testing.T) {
+	var requests int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode([]issue{{Number: 1}})
+	}))
+	defer mockServer.Close()
+
+	clock := itertest.NewClock(time.Now())
+	iterator := iter.NewGitHub[issue](iter.GitHubConfig{
+		HTTP: iter.HTTP{Clock: clock},
+		URL:  mockServer.URL,
+	})
+
+	done := make(chan error)
+	go func() {
+		_, err := iterator.Get()
+		done <- err
+	}()
+
+	clock.BlockUntil(1)
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Fatalf("expected request to wait for the rate limit, got %d requests", n)
+	}
+	clock.Advance(time.Minute)
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("expected 2 requests, got %d", n)
+	}
+}
+
+func TestNewGitHubBackoff(t *testing.T) {
+	var requests int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer mockServer.Close()
+
+	clock := itertest.NewClock(time.Now())
+	iterator := iter.NewGitHub[issue](iter.GitHubConfig{
+		HTTP:       iter.HTTP{Clock: clock},
+		URL:        mockServer.URL,
+		MaxRetries: 2,
+	})
+
+	done := make(chan error)
+	go func() {
+		_, err := iterator.Get()
+		done <- err
+	}()
+
+	for i, delay := range []time.Duration{time.Second, 2 * time.Second} {
+		clock.BlockUntil(1)
+		if n := atomic.LoadInt32(&requests); n != int32(i+1) {
+			t.Fatalf("expected %d requests before backoff, got %d", i+1, n)
+		}
+		clock.Advance(delay)
+	}
+
+	var statusErr *iter.StatusError
+	if err := <-done; !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Errorf("expected 3 requests, got %d", n)
+	}
+}
@@ -0,0 +1,27 @@
+package iter
+
+// NewPageToken creates a cursor over Google style APIs paginated with
+// pageToken and nextPageToken fields. list should call the API with the
+// page token, empty for the first page, e.g. with generated clients:
+//
+//	func(token string) (*drive.FileList, error) {
+//		return srv.Files.List().PageToken(token).Do()
+//	}
+//
+// nextPageToken returns the token of the next page from the response.
+// The iteration ends when it is empty.
+func NewPageToken[Response any](
+	list func(pageToken string) (Response, error),
+	nextPageToken func(response Response) string,
+) *Cursor[string, Response] {
+	return New(Config[string, Response]{
+		HasNext: func(result Response) (string, bool) {
+			token := nextPageToken(result)
+			return token, token != ""
+		},
+		FetchNext: list,
+		GetFirstInput: func() string {
+			return ""
+		},
+	})
+}
@@ -0,0 +1,40 @@
+package iter_test
+
+import (
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+type fileList struct {
+	Files         []string
+	NextPageToken string
+}
+
+func TestNewPageToken(t *testing.T) {
+	pages := map[string]fileList{
+		"":   {Files: []string{"a", "b"}, NextPageToken: "t1"},
+		"t1": {Files: []string{"c"}, NextPageToken: "t2"},
+		"t2": {Files: nil, NextPageToken: ""},
+	}
+
+	iterator := iter.NewPageToken(func(token string) (*fileList, error) {
+		page := pages[token]
+		return &page, nil
+	}, func(response *fileList) string {
+		return response.NextPageToken
+	})
+
+	var files []string
+	err := iterator.Iterate(func(response *fileList) error {
+		files = append(files, response.Files...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(files, []string{"a", "b", "c"}) {
+		t.Errorf("unexpected files: %+v", files)
+	}
+}
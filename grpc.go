@@ -0,0 +1,47 @@
+package iter
+
+import "context"
+
+// PagedRPCConfig configures iteration over unary RPCs paginated
+// according to AIP-158 (page_size, page_token and next_page_token).
+type PagedRPCConfig[Request, Response any] struct {
+	// PageSize is the requested page size, zero leaves it up to the
+	// server.
+	PageSize int32
+	// NewRequest builds the request for the page, e.g.
+	//
+	//	func(size int32, token string) *pb.ListBooksRequest {
+	//		return &pb.ListBooksRequest{Parent: parent, PageSize: size, PageToken: token}
+	//	}
+	NewRequest func(pageSize int32, pageToken string) Request
+	// Call invokes the RPC, e.g.
+	//
+	//	func(ctx context.Context, req *pb.ListBooksRequest) (*pb.ListBooksResponse, error) {
+	//		return client.ListBooks(ctx, req)
+	//	}
+	Call func(ctx context.Context, request Request) (Response, error)
+	// NextPageToken returns next_page_token of the response, e.g.
+	// (*pb.ListBooksResponse).GetNextPageToken.
+	NextPageToken func(response Response) string
+}
+
+// NewPagedRPC creates a cursor over responses of a paged unary RPC. The
+// page token is used as input, empty for the first page, and the
+// iteration ends when the response has no next_page_token.
+func NewPagedRPC[Request, Response any](
+	ctx context.Context,
+	config PagedRPCConfig[Request, Response],
+) *Cursor[string, Response] {
+	return New(Config[string, Response]{
+		HasNext: func(result Response) (string, bool) {
+			token := config.NextPageToken(result)
+			return token, token != ""
+		},
+		FetchNext: func(input string) (Response, error) {
+			return config.Call(ctx, config.NewRequest(config.PageSize, input))
+		},
+		GetFirstInput: func() string {
+			return ""
+		},
+	})
+}
@@ -0,0 +1,64 @@
+package iter_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+type listBooksRequest struct {
+	PageSize  int32
+	PageToken string
+}
+
+type listBooksResponse struct {
+	Books         []string
+	NextPageToken string
+}
+
+func (r *listBooksResponse) GetNextPageToken() string {
+	return r.NextPageToken
+}
+
+func TestNewPagedRPC(t *testing.T) {
+	books := []string{"a", "b", "c", "d", "e"}
+
+	listBooks := func(ctx context.Context, req *listBooksRequest) (*listBooksResponse, error) {
+		start := 0
+		for i, book := range books {
+			if book == req.PageToken {
+				start = i
+			}
+		}
+		end := start + int(req.PageSize)
+		if end >= len(books) {
+			return &listBooksResponse{Books: books[start:]}, nil
+		}
+		return &listBooksResponse{Books: books[start:end], NextPageToken: books[end]}, nil
+	}
+
+	iterator := iter.NewPagedRPC(context.Background(), iter.PagedRPCConfig[*listBooksRequest, *listBooksResponse]{
+		PageSize: 2,
+		NewRequest: func(size int32, token string) *listBooksRequest {
+			return &listBooksRequest{PageSize: size, PageToken: token}
+		},
+		Call:          listBooks,
+		NextPageToken: (*listBooksResponse).GetNextPageToken,
+	})
+
+	var pages [][]string
+	err := iterator.Iterate(func(response *listBooksResponse) error {
+		pages = append(pages, response.Books)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := [][]string{{"a", "b"}, {"c", "d"}, {"e"}}
+	if !reflect.DeepEqual(pages, expected) {
+		t.Errorf("unexpected pages: %+v", pages)
+	}
+}
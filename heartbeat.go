@@ -0,0 +1,139 @@
+package iter
+
+import (
+	"sync"
+	"time"
+)
+
+// HeartbeatStats are passed to heartbeat callbacks.
+type HeartbeatStats struct {
+	// Pages is the number of successfully fetched results.
+	Pages int
+	// Fetching reports whether a fetch is in flight.
+	Fetching bool
+	// FetchStarted is the start of the in-flight or last fetch.
+	FetchStarted time.Time
+	// LastPage is the time the last result was fetched.
+	LastPage time.Time
+}
+
+// Heartbeat returns middleware calling beat every interval, including
+// while a fetch is in flight, so orchestration systems can tell a stuck
+// iteration from a slow one. Beats start with the first fetch and stop
+// once the cursor is depleted, a fetch fails or stop is called. Call
+// stop when abandoning the iteration early. Clock is used for timing,
+// SystemClock if nil.
+func Heartbeat[Input, Result any](
+	clock Clock,
+	interval time.Duration,
+	beat func(stats HeartbeatStats),
+) (middleware Middleware[Input, Result], stop func()) {
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	var (
+		mu      sync.Mutex
+		stats   HeartbeatStats
+		running bool
+		done    chan struct{}
+	)
+
+	start := func() {
+		if running {
+			return
+		}
+		running = true
+		done = make(chan struct{})
+
+		go func(done chan struct{}) {
+			for {
+				select {
+				case <-clock.After(interval):
+					mu.Lock()
+					current := stats
+					mu.Unlock()
+					beat(current)
+				case <-done:
+					return
+				}
+			}
+		}(done)
+	}
+
+	// stopLocked must be called with mu held.
+	stopLocked := func() {
+		if running {
+			running = false
+			close(done)
+		}
+	}
+
+	stop = func() {
+		mu.Lock()
+		defer mu.Unlock()
+		stopLocked()
+	}
+
+	middleware = func(config Config[Input, Result]) Config[Input, Result] {
+		var (
+			before  int
+			refetch bool
+		)
+
+		getFirstInput := config.GetFirstInput
+		config.GetFirstInput = func() Input {
+			mu.Lock()
+			stats = HeartbeatStats{}
+			refetch = false
+			mu.Unlock()
+			return getFirstInput()
+		}
+
+		config.onRefetch(func() {
+			mu.Lock()
+			refetch = true
+			mu.Unlock()
+		})
+
+		config.wrapFetch(func(fetchNext func(input Input) (Result, error)) func(input Input) (Result, error) {
+			return func(input Input) (Result, error) {
+				mu.Lock()
+				pages := stats.Pages
+				if refetch {
+					pages, refetch = before, false
+				}
+				start()
+				stats.Fetching = true
+				stats.FetchStarted = clock.Now()
+				mu.Unlock()
+
+				result, err := fetchNext(input)
+
+				mu.Lock()
+				stats.Fetching = false
+				if err == nil {
+					before, stats.Pages = pages, pages+1
+					stats.LastPage = clock.Now()
+				} else {
+					stopLocked()
+				}
+				mu.Unlock()
+				return result, err
+			}
+		})
+
+		hasNext := config.HasNext
+		config.HasNext = func(result Result) (Input, bool) {
+			input, ok := hasNext(result)
+			if !ok {
+				stop()
+			}
+			return input, ok
+		}
+
+		return config
+	}
+
+	return middleware, stop
+}
@@ -0,0 +1,82 @@
+package iter_test
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"go.teddydd.me/iter"
+	"go.teddydd.me/iter/itertest"
+)
+
+func TestHeartbeat(t *testing.T) {
+	clock := itertest.NewClock(time.Now())
+	beats := make(chan iter.HeartbeatStats)
+
+	heartbeat, stop := iter.Heartbeat[int, []int](clock, time.Second, func(stats iter.HeartbeatStats) {
+		beats <- stats
+	})
+	defer stop()
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	config := pagesConfig([][]int{{1}, {2}, {3}})
+	fetchNext := config.FetchNext
+	config.FetchNext = func(input int) ([]int, error) {
+		if input == 1 {
+			// the second fetch hangs until released
+			close(entered)
+			<-release
+		}
+		return fetchNext(input)
+	}
+	iterator := iter.New(iter.Use(config, heartbeat))
+
+	if _, err := iterator.Get(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	if stats := <-beats; stats.Pages != 1 || stats.Fetching {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		iterator.Get()
+		close(done)
+	}()
+	<-entered
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	if stats := <-beats; stats.Pages != 1 || !stats.Fetching {
+		t.Errorf("expected beat during fetch, got %+v", stats)
+	}
+
+	close(release)
+	<-done
+	if _, err := iterator.Get(); err != nil || iterator.Next() {
+		t.Fatalf("expected depleted cursor, got %v", err)
+	}
+}
+
+func TestHeartbeatFetchError(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	heartbeat, _ := iter.Heartbeat[int, []int](itertest.NewClock(time.Now()), time.Second, func(iter.HeartbeatStats) {})
+	config := pagesConfig([][]int{{1}})
+	config.FetchNext = func(int) ([]int, error) {
+		return nil, errors.New("boom")
+	}
+	iterator := iter.New(iter.Use(config, heartbeat))
+
+	// the iteration is aborted without calling stop
+	if err := iterator.Iterate(func([]int) error { return nil }); err == nil {
+		t.Fatal("expected error")
+	}
+
+	checkGoroutines(t, before)
+}
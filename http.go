@@ -0,0 +1,493 @@
+package iter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Doer sends HTTP requests. It is implemented by *http.Client, and can
+// be implemented by test doubles or clients with custom transports.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// HTTP holds settings shared by HTTP based adapters.
+type HTTP struct {
+	// Client sends requests, http.DefaultClient is used if nil.
+	Client Doer
+	// Clock is used to wait for rate limits, SystemClock if nil.
+	Clock Clock
+	// UserAgent is sent with every request if not empty.
+	UserAgent string
+	// Header holds default headers of every request. Headers set by
+	// adapters take precedence.
+	Header http.Header
+	// Prepare, if set, is called with every request before it is sent,
+	// e.g. to add tracing headers or custom authentication.
+	Prepare func(req *http.Request) error
+	// MaxResponseBytes limits size of response bodies as transferred,
+	// unlimited if zero. Reading past the limit fails with
+	// ErrResponseTooLarge.
+	MaxResponseBytes int64
+	// MaxDecodedBytes limits size of response bodies after
+	// decompression, so small compressed bodies can't exhaust memory.
+	// MaxResponseBytes is used if zero.
+	MaxDecodedBytes int64
+	// Retry, if set, retries failed requests, e.g. DefaultRetryPolicy.
+	Retry *RetryPolicy
+	// Redact, if set, formats URLs in errors, so secrets embedded in
+	// them are masked, e.g. RedactQuery("token"). Cursors over URLs use
+	// it as [Config.Redact].
+	Redact func(url string) string
+}
+
+// ErrResponseTooLarge is returned when a response body exceeds
+// MaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response too large")
+
+// drainLimit is the most bytes read from unread bodies on close, so the
+// connection can be reused without downloading huge bodies.
+const drainLimit = 64 << 10
+
+func (h HTTP) do(req *http.Request) (*http.Response, error) {
+	for key, values := range h.Header {
+		if _, ok := req.Header[http.CanonicalHeaderKey(key)]; !ok {
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
+	}
+	if h.UserAgent != "" {
+		req.Header.Set("User-Agent", h.UserAgent)
+	}
+	if h.Prepare != nil {
+		if err := h.Prepare(req); err != nil {
+			return nil, err
+		}
+	}
+
+	var client Doer = http.DefaultClient
+	if h.Client != nil {
+		client = h.Client
+	}
+	resp, err := h.Retry.send(client, h.clock(), req)
+	if err != nil {
+		return nil, h.redactError(err)
+	}
+
+	body := &responseBody{ReadCloser: resp.Body, limit: h.MaxResponseBytes}
+	if h.MaxResponseBytes > 0 {
+		if resp.ContentLength > h.MaxResponseBytes {
+			body.Close()
+			return nil, fmt.Errorf("%w: %d bytes (%s)", ErrResponseTooLarge, resp.ContentLength, h.redact(req.URL.String()))
+		}
+		body.reader = http.MaxBytesReader(nil, resp.Body, h.MaxResponseBytes)
+	}
+	resp.Body = body
+	return resp, nil
+}
+
+func (h HTTP) maxDecodedBytes() int64 {
+	if h.MaxDecodedBytes != 0 {
+		return h.MaxDecodedBytes
+	}
+	return h.MaxResponseBytes
+}
+
+// redact formats rawURL for errors with Redact.
+func (h HTTP) redact(rawURL string) string {
+	if h.Redact == nil {
+		return rawURL
+	}
+	return h.Redact(rawURL)
+}
+
+// redactError masks the URL of *url.Error returned by clients and
+// request constructors.
+func (h HTTP) redactError(err error) error {
+	var urlErr *url.Error
+	if h.Redact != nil && errors.As(err, &urlErr) {
+		urlErr.URL = h.Redact(urlErr.URL)
+	}
+	return err
+}
+
+// responseBody enforces the size limit of a response body and drains
+// it on close.
+type responseBody struct {
+	io.ReadCloser
+	reader io.Reader
+	limit  int64
+}
+
+func (b *responseBody) Read(p []byte) (int, error) {
+	if b.reader == nil {
+		return b.ReadCloser.Read(p)
+	}
+
+	n, err := b.reader.Read(p)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		err = fmt.Errorf("%w: over %d bytes", ErrResponseTooLarge, b.limit)
+	}
+	return n, err
+}
+
+func (b *responseBody) Close() error {
+	io.CopyN(io.Discard, b.ReadCloser, drainLimit)
+	return b.ReadCloser.Close()
+}
+
+func (h HTTP) clock() Clock {
+	if h.Clock == nil {
+		return SystemClock
+	}
+	return h.Clock
+}
+
+// StatusError is returned by HTTP based adapters when a response has
+// unexpected status code.
+type StatusError struct {
+	StatusCode int
+	URL        string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d (%s)", e.StatusCode, e.URL)
+}
+
+// linkTarget returns target of the link with given relation from Link
+// header (RFC 8288), or empty string if there is none.
+func linkTarget(header http.Header, rel string) string {
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			for _, param := range parts[1:] {
+				key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(key, "rel") {
+					continue
+				}
+				for _, r := range strings.Fields(strings.Trim(value, `"`)) {
+					if strings.EqualFold(r, rel) {
+						return target[1 : len(target)-1]
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// retryAfter returns the delay requested by Retry-After header in
+// seconds.
+func retryAfter(header http.Header) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	return time.Duration(seconds) * time.Second, err == nil
+}
+
+// Decoder decodes a response body into v.
+type Decoder func(data []byte, v any) error
+
+// ErrUnsupportedMediaType is returned when there is no decoder for the
+// Content-Type of a response.
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// selectDecoder returns the decoder for the media type of the response.
+// Bodies without specific media type are sniffed for JSON and XML.
+func selectDecoder(decoders map[string]Decoder, header http.Header, body []byte) (Decoder, error) {
+	contentType := header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil && contentType != "" {
+		return nil, err
+	}
+
+	if decoder, ok := decoders[mediaType]; ok {
+		return decoder, nil
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return json.Unmarshal, nil
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return xml.Unmarshal, nil
+	case mediaType == "" || mediaType == "text/plain" || mediaType == "application/octet-stream":
+		trimmed := bytes.TrimSpace(body)
+		if bytes.HasPrefix(trimmed, []byte("<")) {
+			return xml.Unmarshal, nil
+		}
+		if bytes.HasPrefix(trimmed, []byte("{")) || bytes.HasPrefix(trimmed, []byte("[")) {
+			return json.Unmarshal, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+}
+
+// accept returns value of the Accept header for the decoders.
+func accept(decoders map[string]Decoder) string {
+	if len(decoders) == 0 {
+		return ""
+	}
+
+	mediaTypes := make([]string, 0, len(decoders))
+	for mediaType := range decoders {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+	return strings.Join(mediaTypes, ", ")
+}
+
+// HTTPConfig configures iteration over HTTP responses.
+type HTTPConfig[Response any] struct {
+	HTTP
+	// URL of the first page.
+	URL string
+	// Next returns the URL of the page following the response, empty for
+	// the last one. The "next" link from the Link header is followed if
+	// nil.
+	Next func(header http.Header, response Response) string
+	// Decoders maps media types to decoders of response bodies. JSON and
+	// XML are decoded with encoding/json and encoding/xml unless
+	// overridden.
+	Decoders map[string]Decoder
+	// Decode, if set, decodes every response regardless of Content-Type.
+	Decode Decoder
+	// Reuse decodes every response into the previous one, reusing its
+	// memory, instead of allocating a new Response. Results are then
+	// only valid until the next Get.
+	Reuse bool
+	// Decompressors maps content codings to decompressors, in addition
+	// to built-in gzip and deflate. Register zstd here.
+	Decompressors map[string]Decompressor
+	// Stats, if set, counts requests and body bytes.
+	Stats *HTTPStats
+	// Cache, if set, stores responses with ETag or Last-Modified and
+	// makes requests conditional. Cached body is decoded again when the
+	// server responds with 304 Not Modified.
+	Cache Cache
+	// StaleIfError serves the cached response of the URL when the request
+	// fails or the server responds with 5xx status, for read paths where
+	// availability beats freshness. All responses are cached then.
+	// Responses implementing StaleMarker are marked.
+	StaleIfError bool
+}
+
+// StaleMarker is implemented by responses that record being served
+// from cache because of err, see HTTPConfig.StaleIfError.
+type StaleMarker interface {
+	MarkStale(err error)
+}
+
+// NewHTTP creates a cursor over HTTP responses decoded into Response by
+// decoder selected by Content-Type. Bodies are read into pooled
+// buffers, so decoders must not retain the data. If Response is a
+// pointer, a new value is allocated and passed to the decoder, so
+// generated protobuf messages can be used with decoders such as:
+//
+//	func(data []byte, v any) error {
+//		return proto.Unmarshal(data, v.(proto.Message))
+//	}
+func NewHTTP[Response any](config HTTPConfig[Response]) *Cursor[string, Response] {
+	next := config.Next
+	if next == nil {
+		next = func(header http.Header, response Response) string {
+			return linkTarget(header, "next")
+		}
+	}
+
+	return newHTTP(config, func(input string, header http.Header, response Response) string {
+		return next(header, response)
+	})
+}
+
+// newHTTP creates the cursor of [NewHTTP] with next link returned for
+// the requested URL.
+func newHTTP[Response any](
+	config HTTPConfig[Response],
+	next func(input string, header http.Header, response Response) string,
+) *Cursor[string, Response] {
+	var (
+		nextURL  string
+		previous Response
+	)
+
+	return New(Config[string, Response]{
+		HasNext: func(result Response) (string, bool) {
+			return nextURL, nextURL != ""
+		},
+		FetchNextContext: func(ctx context.Context, input string) (Response, error) {
+			var response Response
+			nextURL = ""
+			if config.Reuse {
+				response = recycle(previous)
+			}
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, input, nil)
+			if err != nil {
+				return response, config.redactError(err)
+			}
+			if accept := accept(config.Decoders); accept != "" {
+				req.Header.Set("Accept", accept)
+			}
+			req.Header.Set("Accept-Encoding", acceptEncoding(config.Decompressors))
+
+			var (
+				cached CachedResponse
+				hit    bool
+			)
+			if config.Cache != nil {
+				if cached, hit = config.Cache.Get(input); hit {
+					if cached.ETag != "" {
+						req.Header.Set("If-None-Match", cached.ETag)
+					}
+					if cached.LastModified != "" {
+						req.Header.Set("If-Modified-Since", cached.LastModified)
+					}
+				}
+			}
+
+			resp, err := config.do(req)
+			var failure error
+			switch {
+			case err != nil:
+				failure = err
+			case resp.StatusCode >= http.StatusInternalServerError:
+				failure = &StatusError{StatusCode: resp.StatusCode, URL: config.redact(input)}
+			}
+			if err == nil {
+				defer resp.Body.Close()
+			}
+
+			stale := failure != nil && hit && config.StaleIfError
+			if failure != nil && !stale {
+				return response, failure
+			}
+			useCached := stale || (resp.StatusCode == http.StatusNotModified && hit)
+			if !useCached && resp.StatusCode != http.StatusOK {
+				return response, &StatusError{StatusCode: resp.StatusCode, URL: config.redact(input)}
+			}
+
+			target := any(&response)
+			if t := typeOf[Response](); t.Kind() == reflect.Pointer {
+				if reflect.ValueOf(&response).Elem().IsNil() {
+					response = reflect.New(t.Elem()).Interface().(Response)
+				}
+				target = response
+			}
+
+			header, body := cached.Header, cached.Body
+			if !useCached {
+				reader, release, err := decompress(resp, config.Decompressors, config.Stats, config.maxDecodedBytes())
+				if err != nil {
+					return response, err
+				}
+				defer release()
+
+				buffer := buffers.Get().(*bytes.Buffer)
+				defer putBuffer(buffer)
+				buffer.Reset()
+				if _, err := buffer.ReadFrom(reader); err != nil {
+					return response, err
+				}
+				header, body = resp.Header, buffer.Bytes()
+
+				etag, lastModified := header.Get("ETag"), header.Get("Last-Modified")
+				if config.Cache != nil && (etag != "" || lastModified != "" || config.StaleIfError) {
+					config.Cache.Put(input, CachedResponse{
+						ETag:         etag,
+						LastModified: lastModified,
+						Header:       header.Clone(),
+						Body:         bytes.Clone(body),
+					})
+				}
+			}
+
+			decode := config.Decode
+			if decode == nil {
+				if decode, err = selectDecoder(config.Decoders, header, body); err != nil {
+					return response, err
+				}
+			}
+			if err := decode(body, target); err != nil {
+				return response, err
+			}
+			if stale {
+				if marker, ok := target.(StaleMarker); ok {
+					marker.MarkStale(failure)
+				}
+			}
+			previous = response
+
+			if link := next(input, header, response); link != "" {
+				nextURL, err = ResolveURL(input, link)
+			}
+			return response, config.redactError(err)
+		},
+		GetFirstInput: func() string {
+			nextURL = ""
+			return config.URL
+		},
+		Redact: config.Redact,
+	})
+}
+
+var buffers = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// maxPooledBuffer is the capacity of the largest buffer returned to
+// the pool, so a single huge response doesn't stay in memory.
+const maxPooledBuffer = 1 << 20
+
+func putBuffer(buffer *bytes.Buffer) {
+	if buffer.Cap() > maxPooledBuffer {
+		return
+	}
+	buffers.Put(buffer)
+}
+
+// recycle returns the previous response prepared for decoding into it.
+// Pointed values and slice elements are zeroed, but their memory is
+// kept. Other types are not reused.
+func recycle[T any](previous T) T {
+	v := reflect.ValueOf(&previous).Elem()
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			v.Elem().Set(reflect.Zero(v.Elem().Type()))
+			return previous
+		}
+	case reflect.Slice:
+		full := v.Slice(0, v.Cap())
+		zero := reflect.Zero(v.Type().Elem())
+		for i := 0; i < full.Len(); i++ {
+			full.Index(i).Set(zero)
+		}
+		v.Set(v.Slice(0, 0))
+		return previous
+	}
+
+	var zero T
+	return zero
+}
@@ -0,0 +1,398 @@
+package iter_test
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.teddydd.me/iter"
+	"go.teddydd.me/iter/itertest"
+)
+
+type message interface {
+	Unmarshal(data []byte) error
+}
+
+type numberPage struct {
+	Numbers []int `json:"numbers"`
+}
+
+func (p *numberPage) Unmarshal(data []byte) error {
+	for _, field := range strings.Fields(string(data)) {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return err
+		}
+		p.Numbers = append(p.Numbers, n)
+	}
+	return nil
+}
+
+func numberPagesHandler(contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 2 {
+			w.Header().Set("Link", fmt.Sprintf(`</?page=%d>; rel="next"`, page+1))
+		}
+		w.Header().Set("Content-Type", contentType)
+
+		if contentType == "application/json" {
+			fmt.Fprintf(w, `{"numbers":[%d,%d]}`, 2*page, 2*page+1)
+		} else {
+			fmt.Fprintf(w, "%d %d", 2*page, 2*page+1)
+		}
+	}
+}
+
+func collectNumbers(t *testing.T, iterator *iter.Cursor[string, *numberPage]) ([]int, error) {
+	t.Helper()
+
+	var numbers []int
+	err := iterator.Iterate(func(response *numberPage) error {
+		numbers = append(numbers, response.Numbers...)
+		return nil
+	})
+	return numbers, err
+}
+
+func TestNewHTTP(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		mockServer := httptest.NewServer(numberPagesHandler("application/json"))
+		defer mockServer.Close()
+
+		numbers, err := collectNumbers(t, iter.NewHTTP(iter.HTTPConfig[*numberPage]{URL: mockServer.URL}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(numbers, []int{0, 1, 2, 3, 4, 5}) {
+			t.Errorf("unexpected numbers: %v", numbers)
+		}
+	})
+
+	t.Run("custom decoder", func(t *testing.T) {
+		mockServer := httptest.NewServer(numberPagesHandler("application/x-protobuf"))
+		defer mockServer.Close()
+
+		iterator := iter.NewHTTP(iter.HTTPConfig[*numberPage]{
+			Decoders: map[string]iter.Decoder{
+				"application/x-protobuf": func(data []byte, v any) error {
+					return v.(message).Unmarshal(data)
+				},
+			},
+			URL: mockServer.URL,
+		})
+
+		numbers, err := collectNumbers(t, iterator)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(numbers, []int{0, 1, 2, 3, 4, 5}) {
+			t.Errorf("unexpected numbers: %v", numbers)
+		}
+	})
+
+	t.Run("unsupported media type", func(t *testing.T) {
+		mockServer := httptest.NewServer(numberPagesHandler("application/x-protobuf"))
+		defer mockServer.Close()
+
+		_, err := collectNumbers(t, iter.NewHTTP(iter.HTTPConfig[*numberPage]{URL: mockServer.URL}))
+		if !errors.Is(err, iter.ErrUnsupportedMediaType) {
+			t.Errorf("expected ErrUnsupportedMediaType, got %v", err)
+		}
+	})
+}
+
+type listBucketResult struct {
+	Keys                  []string `xml:"Contents>Key"`
+	NextContinuationToken string
+}
+
+func TestNewHTTPXML(t *testing.T) {
+	pages := map[string]string{
+		"":   "<ListBucketResult><Contents><Key>a</Key></Contents><Contents><Key>b</Key></Contents><NextContinuationToken>t1</NextContinuationToken></ListBucketResult>",
+		"t1": "<ListBucketResult><Contents><Key>c</Key></Contents></ListBucketResult>",
+	}
+
+	for _, contentType := range []string{"application/xml", "application/octet-stream", ""} {
+		t.Run(contentType, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header()["Content-Type"] = []string{contentType}
+				fmt.Fprint(w, pages[r.URL.Query().Get("continuation-token")])
+			}))
+			defer mockServer.Close()
+
+			iterator := iter.NewHTTP(iter.HTTPConfig[listBucketResult]{
+				URL: mockServer.URL,
+				Next: func(header http.Header, response listBucketResult) string {
+					if response.NextContinuationToken == "" {
+						return ""
+					}
+					return "?continuation-token=" + response.NextContinuationToken
+				},
+			})
+
+			var keys []string
+			err := iterator.Iterate(func(response listBucketResult) error {
+				keys = append(keys, response.Keys...)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+				t.Errorf("unexpected keys: %v", keys)
+			}
+		})
+	}
+
+	t.Run("decode option", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/vnd.legacy")
+			fmt.Fprint(w, pages["t1"])
+		}))
+		defer mockServer.Close()
+
+		iterator := iter.NewHTTP(iter.HTTPConfig[listBucketResult]{
+			URL:    mockServer.URL,
+			Decode: xml.Unmarshal,
+		})
+		response, err := iterator.Get()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(response.Keys, []string{"c"}) {
+			t.Errorf("unexpected keys: %v", response.Keys)
+		}
+	})
+}
+
+func TestNewHTTPReuse(t *testing.T) {
+	mockServer := httptest.NewServer(numberPagesHandler("application/json"))
+	defer mockServer.Close()
+
+	t.Run("pointer", func(t *testing.T) {
+		iterator := iter.NewHTTP(iter.HTTPConfig[*numberPage]{URL: mockServer.URL, Reuse: true})
+
+		first, err := iterator.Get()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		second, err := iterator.Get()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if first != second {
+			t.Errorf("expected the response to be reused")
+		}
+		if !reflect.DeepEqual(second.Numbers, []int{2, 3}) {
+			t.Errorf("unexpected numbers: %v", second.Numbers)
+		}
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		server := itertest.NewServer(itertest.ServerConfig{Records: 5, PageSize: 3, Style: itertest.Link})
+		defer server.Close()
+
+		iterator := iter.NewHTTP(iter.HTTPConfig[[]itertest.Record]{URL: server.URL, Reuse: true})
+
+		first, err := iterator.Get()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		second, err := iterator.Get()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if &first[0] != &second[0] {
+			t.Errorf("expected the backing array to be reused")
+		}
+		if !reflect.DeepEqual(second, []itertest.Record{{ID: 4}, {ID: 5}}) {
+			t.Errorf("unexpected records: %v", second)
+		}
+	})
+}
+
+func TestHTTPRequestHeaders(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("User-Agent"); got != "exporter/1.0" {
+			t.Errorf("unexpected user agent: %q", got)
+		}
+		if got := r.Header.Get("X-Tenant"); got != "acme" {
+			t.Errorf("unexpected tenant header: %q", got)
+		}
+		if got := r.Header.Get("Accept"); got != "application/xml" {
+			t.Errorf("default header overrode adapter header: %q", got)
+		}
+		if got := r.Header.Get("Traceparent"); got != "00-trace" {
+			t.Errorf("unexpected trace header: %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"numbers": [1]}`)
+	}))
+	defer mockServer.Close()
+
+	iterator := iter.NewHTTP(iter.HTTPConfig[numberPage]{
+		HTTP: iter.HTTP{
+			UserAgent: "exporter/1.0",
+			Header: http.Header{
+				"X-Tenant": {"acme"},
+				"Accept":   {"text/plain"},
+			},
+			Prepare: func(req *http.Request) error {
+				req.Header.Set("Traceparent", "00-trace")
+				return nil
+			},
+		},
+		URL:      mockServer.URL,
+		Decoders: map[string]iter.Decoder{"application/xml": xml.Unmarshal},
+	})
+	if _, err := iterator.Get(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	failure := errors.New("no credentials")
+	iterator = iter.NewHTTP(iter.HTTPConfig[numberPage]{
+		HTTP: iter.HTTP{
+			Prepare: func(req *http.Request) error {
+				return failure
+			},
+		},
+		URL: mockServer.URL,
+	})
+	if _, err := iterator.Get(); !errors.Is(err, failure) {
+		t.Errorf("expected prepare error, got %v", err)
+	}
+}
+
+var _ iter.Doer = http.DefaultClient
+
+// doerFunc is a Doer serving responses without network.
+type doerFunc func(req *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestHTTPClient(t *testing.T) {
+	handler := numberPagesHandler("application/json")
+
+	iterator := iter.NewHTTP(iter.HTTPConfig[*numberPage]{
+		HTTP: iter.HTTP{
+			Client: doerFunc(func(req *http.Request) (*http.Response, error) {
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, req)
+				return recorder.Result(), nil
+			}),
+		},
+		URL: "http://example.com/",
+	})
+	numbers, err := collectNumbers(t, iterator)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(numbers, []int{0, 1, 2, 3, 4, 5}) {
+		t.Errorf("unexpected numbers: %v", numbers)
+	}
+}
+
+func TestHTTPMaxResponseBytes(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("chunked") != "" {
+			// flushing before writing hides the length of the body
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprintf(w, `{"numbers": [%s1]}`, strings.Repeat("1, ", 100))
+	}))
+	defer mockServer.Close()
+
+	for _, url := range []string{mockServer.URL, mockServer.URL + "?chunked=1"} {
+		iterator := iter.NewHTTP(iter.HTTPConfig[numberPage]{
+			HTTP: iter.HTTP{MaxResponseBytes: 100},
+			URL:  url,
+		})
+		if _, err := iterator.Get(); !errors.Is(err, iter.ErrResponseTooLarge) {
+			t.Errorf("%s: expected ErrResponseTooLarge, got %v", url, err)
+		}
+	}
+}
+
+func TestNewHTTPContext(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer mockServer.Close()
+
+	iterator := iter.NewHTTP(iter.HTTPConfig[numberPage]{URL: mockServer.URL})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := iterator.GetContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestNewHTTPRedact(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/large" {
+			w.Header().Set("Content-Length", "1000")
+			w.Write(make([]byte, 1000))
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer mockServer.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	for _, url := range []string{mockServer.URL, mockServer.URL + "/large", closed.URL} {
+		iterator := iter.NewHTTP(iter.HTTPConfig[numberPage]{
+			URL: url + "?token=secret",
+			HTTP: iter.HTTP{
+				MaxResponseBytes: 100,
+				Redact:           iter.RedactQuery("token"),
+			},
+		})
+		err := iter.RunShards([]iter.Shard[string, numberPage]{{Name: "a", Cursor: iterator}}, 1, func(string, numberPage) error {
+			return nil
+		})
+		if err == nil || strings.Contains(err.Error(), "secret") || !strings.Contains(err.Error(), "REDACTED") {
+			t.Errorf("expected redacted error for %s, got %v", url, err)
+		}
+	}
+}
+
+func TestHTTPDrainsBodies(t *testing.T) {
+	var connections int32
+	mockServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, strings.Repeat("error ", 5000))
+	}))
+	mockServer.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	mockServer.Start()
+	defer mockServer.Close()
+
+	for i := 0; i < 3; i++ {
+		iterator := iter.NewHTTP(iter.HTTPConfig[numberPage]{URL: mockServer.URL})
+		var statusErr *iter.StatusError
+		if _, err := iterator.Get(); !errors.As(err, &statusErr) {
+			t.Fatalf("expected StatusError, got %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(&connections); n != 1 {
+		t.Errorf("expected connection to be reused, got %d connections", n)
+	}
+}
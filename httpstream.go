@@ -0,0 +1,151 @@
+package iter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// HTTPItemsConfig configures streaming of items of JSON pages.
+type HTTPItemsConfig struct {
+	HTTP
+	// URL of the first page.
+	URL string
+	// Next returns the URL of the page following the one with given
+	// headers, empty for the last one. The "next" link from the Link
+	// header is followed if nil.
+	Next func(header http.Header) string
+	// Field is the name of the top level field holding the array of
+	// items. Pages are expected to be arrays if empty.
+	Field string
+}
+
+// StreamHTTP returns stream of items of JSON pages. Items are decoded
+// one by one as they arrive, so huge pages are never held in memory.
+// If decoding fails, the page is fetched again on the next Get and its
+// items already yielded are skipped.
+func StreamHTTP[Item any](config HTTPItemsConfig) *Stream[Item] {
+	next := config.Next
+	if next == nil {
+		next = func(header http.Header) string {
+			return linkTarget(header, "next")
+		}
+	}
+
+	var (
+		url      = config.URL
+		nextURL  string
+		body     io.ReadCloser
+		decoder  *json.Decoder
+		position int
+	)
+
+	closePage := func() {
+		if body != nil {
+			body.Close()
+		}
+		body, decoder = nil, nil
+	}
+
+	openPage := func() error {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return config.redactError(err)
+		}
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := config.do(req)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return &StatusError{StatusCode: resp.StatusCode, URL: config.redact(url)}
+		}
+
+		nextURL = ""
+		if link := next(resp.Header); link != "" {
+			if nextURL, err = ResolveURL(url, link); err != nil {
+				resp.Body.Close()
+				return config.redactError(err)
+			}
+		}
+		body, decoder = resp.Body, json.NewDecoder(resp.Body)
+
+		if config.Field != "" {
+			if err := expectDelim(decoder, '{'); err != nil {
+				return err
+			}
+			for {
+				if !decoder.More() {
+					return fmt.Errorf("field %q not found in %s", config.Field, url)
+				}
+				key, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				if key == config.Field {
+					break
+				}
+				var skip json.RawMessage
+				if err := decoder.Decode(&skip); err != nil {
+					return err
+				}
+			}
+		}
+		if err := expectDelim(decoder, '['); err != nil {
+			return err
+		}
+
+		// skip items yielded before a failure
+		for i := 0; i < position && decoder.More(); i++ {
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return newStream(func() (Item, error) {
+		var item Item
+		for {
+			if decoder == nil {
+				if url == "" {
+					return item, ErrStop
+				}
+				if err := openPage(); err != nil {
+					closePage()
+					return item, err
+				}
+			}
+
+			if decoder.More() {
+				if err := decoder.Decode(&item); err != nil {
+					closePage()
+					return item, err
+				}
+				position++
+				return item, nil
+			}
+
+			closePage()
+			url, position = nextURL, 0
+		}
+	}, func() {
+		closePage()
+		url, nextURL, position = config.URL, "", 0
+	})
+}
+
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %v, got %v", delim, token)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package iter_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.teddydd.me/iter"
+	"go.teddydd.me/iter/itertest"
+)
+
+func TestStreamHTTP(t *testing.T) {
+	server := itertest.NewServer(itertest.ServerConfig{Records: 7, PageSize: 3, Style: itertest.Link})
+	defer server.Close()
+
+	stream := iter.StreamHTTP[itertest.Record](iter.HTTPItemsConfig{URL: server.URL})
+	records := collect(t, stream)
+	if len(records) != 7 || records[0].ID != 1 || records[6].ID != 7 {
+		t.Errorf("unexpected records: %v", records)
+	}
+
+	stream.Reset()
+	if records := collect(t, stream); len(records) != 7 {
+		t.Errorf("unexpected records after reset: %v", records)
+	}
+}
+
+func TestStreamHTTPField(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			w.Header().Set("Link", `</?page=2>; rel="next"`)
+			fmt.Fprint(w, `{"total": 3, "meta": {"items": [0]}, "items": [{"id": 1}, {"id": 2}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"items": [{"id": 3}]}`)
+	}))
+	defer mockServer.Close()
+
+	stream := iter.StreamHTTP[itertest.Record](iter.HTTPItemsConfig{URL: mockServer.URL, Field: "items"})
+	records := collect(t, stream)
+	if len(records) != 3 || records[2].ID != 3 {
+		t.Errorf("unexpected records: %v", records)
+	}
+}
+
+func TestStreamHTTPRetry(t *testing.T) {
+	var requests int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			fmt.Fprint(w, `[{"id": 1}, {"id": 2}, {"id": "broken"}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"id": 1}, {"id": 2}, {"id": 3}]`)
+	}))
+	defer mockServer.Close()
+
+	stream := iter.StreamHTTP[itertest.Record](iter.HTTPItemsConfig{URL: mockServer.URL})
+
+	var ids []int
+	for stream.Next() {
+		record, err := stream.Get()
+		if err == iter.ErrStop {
+			break
+		}
+		if err != nil {
+			continue
+		}
+		ids = append(ids, record.ID)
+	}
+	if fmt.Sprint(ids) != "[1 2 3]" {
+		t.Errorf("unexpected ids: %v", ids)
+	}
+}
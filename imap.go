@@ -0,0 +1,61 @@
+package iter
+
+// UIDRange is an inclusive range of IMAP message UIDs.
+type UIDRange struct {
+	First uint32
+	Last  uint32
+}
+
+// UIDConfig configures iteration over an IMAP mailbox in UID ranges.
+type UIDConfig[Message any] struct {
+	// LastSeen is the highest UID processed by a previous run, zero to
+	// start from the beginning of the mailbox.
+	LastSeen uint32
+	// UIDNext is the UIDNEXT value of the mailbox reported by SELECT or
+	// STATUS. Messages with lower UIDs are iterated.
+	UIDNext uint32
+	// BatchSize is the number of UIDs in a single range, 1000 if zero.
+	BatchSize uint32
+	// Fetch should fetch messages in the range, e.g. with
+	// UID FETCH first:last.
+	Fetch func(uids UIDRange) ([]Message, error)
+}
+
+// NewUIDRanges creates a cursor fetching messages of an IMAP mailbox in
+// UID ranges. The highest UID covered so far is used as input, so mail
+// ingestion can be resumed by passing it as LastSeen.
+func NewUIDRanges[Message any](config UIDConfig[Message]) *Cursor[uint32, Page[uint32, Message]] {
+	if config.BatchSize == 0 {
+		config.BatchSize = 1000
+	}
+
+	// covered reports whether all UIDs below UIDNext are covered by
+	// ranges up to uid, written so it can't overflow
+	covered := func(uid uint32) bool {
+		return config.UIDNext == 0 || uid >= config.UIDNext-1
+	}
+
+	return New(Config[uint32, Page[uint32, Message]]{
+		HasNext: func(result Page[uint32, Message]) (uint32, bool) {
+			return result.Next, !covered(result.Next)
+		},
+		FetchNext: func(input uint32) (Page[uint32, Message], error) {
+			if covered(input) {
+				return Page[uint32, Message]{Next: input}, nil
+			}
+			uids := UIDRange{First: input + 1, Last: config.UIDNext - 1}
+			if config.BatchSize <= uids.Last-input {
+				uids.Last = input + config.BatchSize
+			}
+
+			messages, err := config.Fetch(uids)
+			if err != nil {
+				return Page[uint32, Message]{Next: input}, err
+			}
+			return Page[uint32, Message]{Items: messages, Next: uids.Last}, nil
+		},
+		GetFirstInput: func() uint32 {
+			return config.LastSeen
+		},
+	})
+}
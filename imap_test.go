@@ -0,0 +1,94 @@
+package iter_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestNewUIDRanges(t *testing.T) {
+	mailbox := []uint32{1, 2, 5, 6, 7, 11}
+	var ranges []iter.UIDRange
+
+	config := iter.UIDConfig[uint32]{
+		UIDNext:   12,
+		BatchSize: 4,
+		Fetch: func(uids iter.UIDRange) ([]uint32, error) {
+			ranges = append(ranges, uids)
+			var messages []uint32
+			for _, uid := range mailbox {
+				if uid >= uids.First && uid <= uids.Last {
+					messages = append(messages, uid)
+				}
+			}
+			return messages, nil
+		},
+	}
+
+	collectUIDs := func(config iter.UIDConfig[uint32]) []uint32 {
+		var results []uint32
+		err := iter.NewUIDRanges(config).Iterate(func(response iter.Page[uint32, uint32]) error {
+			results = append(results, response.Items...)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return results
+	}
+
+	if results := collectUIDs(config); !reflect.DeepEqual(results, mailbox) {
+		t.Errorf("unexpected messages: %+v", results)
+	}
+	expected := []iter.UIDRange{{1, 4}, {5, 8}, {9, 11}}
+	if !reflect.DeepEqual(ranges, expected) {
+		t.Errorf("unexpected ranges: got %+v, want %+v", ranges, expected)
+	}
+
+	t.Run("resume", func(t *testing.T) {
+		ranges = nil
+		config := config
+		config.LastSeen = 6
+		if results := collectUIDs(config); !reflect.DeepEqual(results, []uint32{7, 11}) {
+			t.Errorf("unexpected messages: %+v", results)
+		}
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		ranges = nil
+		config := config
+		config.LastSeen = 11
+		if results := collectUIDs(config); len(results) != 0 {
+			t.Errorf("expected no messages, got %+v", results)
+		}
+		if len(ranges) != 0 {
+			t.Errorf("expected no fetches, got %+v", ranges)
+		}
+	})
+
+	t.Run("default batch size", func(t *testing.T) {
+		ranges = nil
+		config := config
+		config.BatchSize = 0
+		if results := collectUIDs(config); !reflect.DeepEqual(results, mailbox) {
+			t.Errorf("unexpected messages: %+v", results)
+		}
+		if !reflect.DeepEqual(ranges, []iter.UIDRange{{1, 11}}) {
+			t.Errorf("unexpected ranges: %+v", ranges)
+		}
+	})
+
+	t.Run("highest UIDs", func(t *testing.T) {
+		ranges = nil
+		config := config
+		config.LastSeen = math.MaxUint32 - 2
+		config.UIDNext = math.MaxUint32
+		collectUIDs(config)
+		expected := []iter.UIDRange{{math.MaxUint32 - 1, math.MaxUint32 - 1}}
+		if !reflect.DeepEqual(ranges, expected) {
+			t.Errorf("unexpected ranges: got %+v, want %+v", ranges, expected)
+		}
+	})
+}
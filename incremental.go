@@ -0,0 +1,98 @@
+package iter
+
+// IncrementalConfig configures a cursor that starts from a persisted
+// high-water mark (timestamp, ID) and advances it as newer pages are
+// processed.
+type IncrementalConfig[Input, Result, Mark any] struct {
+	// HasNext checks if response indicates there is more Results
+	// to fetch.
+	HasNext func(result Result) (Input, bool)
+	// FetchNext should fetch next Result.
+	FetchNext func(input Input) (Result, error)
+	// GetFirstInput must return initial input that continues after
+	// the given mark.
+	GetFirstInput func(mark Mark) Input
+	// Mark returns the highest mark found in the result. It should
+	// return false if result does not carry any mark, e.g. it is empty.
+	Mark func(result Result) (Mark, bool)
+	// Less reports whether mark a is older than mark b.
+	Less func(a, b Mark) bool
+	// Load returns the last persisted mark.
+	Load func() (Mark, error)
+	// Save persists the mark.
+	Save func(mark Mark) error
+}
+
+// Incremental is a [Cursor] used for incremental syncs. It starts from
+// the persisted high-water mark and saves a newer one after every
+// processed page.
+type Incremental[Input, Result, Mark any] struct {
+	*Cursor[Input, Result]
+
+	mark   Mark
+	markOf func(result Result) (Mark, bool)
+	less   func(a, b Mark) bool
+	save   func(mark Mark) error
+}
+
+// NewIncremental loads the last high-water mark and creates a cursor
+// starting from it.
+func NewIncremental[Input, Result, Mark any](
+	config IncrementalConfig[Input, Result, Mark],
+) (*Incremental[Input, Result, Mark], error) {
+	mark, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Incremental[Input, Result, Mark]{
+		mark:   mark,
+		markOf: config.Mark,
+		less:   config.Less,
+		save:   config.Save,
+	}
+	d.Cursor = New(Config[Input, Result]{
+		HasNext:   config.HasNext,
+		FetchNext: config.FetchNext,
+		GetFirstInput: func() Input {
+			return config.GetFirstInput(d.mark)
+		},
+	})
+
+	return d, nil
+}
+
+// Mark returns the current high-water mark.
+func (d *Incremental[Input, Result, Mark]) Mark() Mark {
+	return d.mark
+}
+
+// Commit saves mark of the result if it is newer than the current one.
+// Call it after processing each result when iterating manually with
+// Next and Get.
+func (d *Incremental[Input, Result, Mark]) Commit(result Result) error {
+	mark, ok := d.markOf(result)
+	if !ok || !d.less(d.mark, mark) {
+		return nil
+	}
+
+	if err := d.save(mark); err != nil {
+		return err
+	}
+
+	d.mark = mark
+	return nil
+}
+
+// Iterate works like [Cursor.Iterate] but commits the mark of every
+// result for which callback returned nil. Results for which callback
+// returned an error, including ErrStop, are not committed.
+func (d *Incremental[Input, Result, Mark]) Iterate(callback func(response Result) error) error {
+	return d.Cursor.Iterate(func(response Result) error {
+		if err := callback(response); err != nil {
+			return err
+		}
+
+		return d.Commit(response)
+	})
+}
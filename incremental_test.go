@@ -0,0 +1,95 @@
+package iter_test
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestIncremental(t *testing.T) {
+	mockServer := httptest.NewServer(MockAPIHandler(5))
+	defer mockServer.Close()
+
+	var saved []int
+	persisted := 1
+	config := iter.IncrementalConfig[int, []Record, int]{
+		HasNext:   nextRecord,
+		FetchNext: fetchRecords(mockServer),
+		GetFirstInput: func(mark int) int {
+			return mark
+		},
+		Mark: nextRecord,
+		Less: func(a, b int) bool {
+			return a < b
+		},
+		Load: func() (int, error) {
+			return persisted, nil
+		},
+		Save: func(mark int) error {
+			saved = append(saved, mark)
+			persisted = mark
+			return nil
+		},
+	}
+
+	iterator, err := iter.NewIncremental(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var results []Record
+	err = iterator.Iterate(func(response []Record) error {
+		results = append(results, response...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(results, []Record{{2}, {3}, {4}, {5}}) {
+		t.Errorf("expected records after mark 1, got %+v", results)
+	}
+	if !reflect.DeepEqual(saved, []int{3, 5}) {
+		t.Errorf("expected marks 3 and 5 to be saved, got %+v", saved)
+	}
+	if iterator.Mark() != 5 {
+		t.Errorf("expected mark 5, got %d", iterator.Mark())
+	}
+
+	t.Run("reset continues from mark", func(t *testing.T) {
+		iterator.Reset()
+		results, err := iterator.Get()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("expected no new records, got %+v", results)
+		}
+	})
+
+	t.Run("next run loads mark", func(t *testing.T) {
+		next, err := iter.NewIncremental(config)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if next.Mark() != 5 {
+			t.Errorf("expected loaded mark 5, got %d", next.Mark())
+		}
+	})
+
+	t.Run("stop does not commit", func(t *testing.T) {
+		persisted = 0
+		saved = nil
+		iterator, _ := iter.NewIncremental(config)
+		err := iterator.Iterate(func(response []Record) error {
+			return iter.ErrStop
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(saved) != 0 {
+			t.Errorf("expected nothing saved, got %+v", saved)
+		}
+	})
+}
@@ -1,20 +1,49 @@
 package iter
 
 import (
+	"context"
 	"errors"
+	"fmt"
 )
 
-var ErrStop = errors.New("iterator stopped")
+var (
+	ErrStop = errors.New("iterator stopped")
+	// ErrNoPage is returned by [Cursor.Retry] if there is no page to
+	// fetch again.
+	ErrNoPage = errors.New("no page to retry")
+	// ErrInvalidResponse wraps errors of [Config.Validate].
+	ErrInvalidResponse = errors.New("invalid response")
+	// ErrSnapshotChanged is returned by Get when the dataset version
+	// reported by a result differs from the one seen on the first page.
+	ErrSnapshotChanged = errors.New("snapshot changed during iteration")
+)
 
 // Cursor can be used to iterate API or database.  It drives iteration with
 // functions provided via [Config].
 type Cursor[Input, Result any] struct {
 	result        Result
 	input         Input
+	last          Input
+	refetch       func(ctx context.Context, input Input) (Result, error)
+	rejected      bool
+	beforeRefetch func()
 	next          bool
 	hasNext       func(result Result) (Input, bool)
-	fetchNext     func(input Input) (Result, error)
+	fetchNext     func(ctx context.Context, input Input) (Result, error)
+	fetchFirst    func(ctx context.Context, input Input) (Result, error)
+	fresh         bool
+	page          int
+	pageContext   func(ctx context.Context, page int, input Input) (context.Context, context.CancelFunc)
 	getFirstInput func() Input
+	start         func(ctx context.Context, input Input) (Input, error)
+	started       bool
+	normalize     func(result Result) Result
+	validate      func(result Result) error
+	version       func(result Result) string
+	snapshot      string
+	name          string
+	labels        map[string]string
+	redact        func(input Input) string
 }
 
 type Config[Input, Result any] struct {
@@ -23,22 +52,125 @@ type Config[Input, Result any] struct {
 	HasNext func(result Result) (Input, bool)
 	// FetchNext should fetch next Result.
 	FetchNext func(input Input) (Result, error)
+	// FetchNextContext is a context-aware variant of FetchNext used
+	// instead of it if set. It receives the context passed to
+	// [Cursor.GetContext], so fetches can be canceled.
+	FetchNextContext func(ctx context.Context, input Input) (Result, error)
+	// FetchFirst is optional. It is used instead of FetchNext to fetch
+	// the first Result, for APIs where the first call differs from
+	// subsequent ones (different endpoint or payload). Once it
+	// succeeds, the cursor switches to FetchNext.
+	FetchFirst func(input Input) (Result, error)
+	// FetchFirstContext is a context-aware variant of FetchFirst used
+	// instead of it if set.
+	FetchFirstContext func(ctx context.Context, input Input) (Result, error)
+	// PageContext is optional. It derives the context of every fetch
+	// from the context of the iteration, e.g. to add per-page
+	// deadlines, tracing baggage or labels. Page is the zero based
+	// index of the page since the cursor was created, reset or
+	// restored.
+	PageContext func(ctx context.Context, page int, input Input) (context.Context, context.CancelFunc)
 	// GetFirstInput must return initial input that can be used by
 	// the cursor.
 	GetFirstInput func() Input
+	// Start is optional. It is called before the first fetch with the
+	// input returned by GetFirstInput and returns the actual first
+	// input, for APIs that must be called to obtain it (initial scroll
+	// ID, export snapshot token). If it fails, Get returns the error
+	// and the next Get calls Start again.
+	Start func(ctx context.Context, input Input) (Input, error)
+	// Normalize is optional. It is applied to every fetched Result
+	// before Validate and HasNext, so defensive cleanup (sorting items,
+	// dropping nulls) is done once for the cursor and its consumers.
+	Normalize func(result Result) Result
+	// Validate is optional. It is called with every fetched Result
+	// before HasNext, e.g. to check schema or signature of the
+	// response. Its error aborts iteration wrapped in
+	// ErrInvalidResponse.
+	Validate func(result Result) error
+	// Version is optional. It should return the dataset version (etag,
+	// snapshot ID) reported in the result. Iteration fails with
+	// ErrSnapshotChanged if it differs from the first seen version.
+	// Empty versions are ignored.
+	Version func(result Result) string
+	// Name and Labels are optional. They identify the cursor in logs,
+	// metrics and the [Registry], e.g. "invoice-backfill".
+	Name   string
+	Labels map[string]string
+	// Redact is optional. It formats inputs in errors and logs, so
+	// secrets embedded in them (tokens, signed URLs) are masked, see
+	// [RedactQuery].
+	Redact func(input Input) string
+	// Refetch is optional. It is called before the input of the last
+	// fetched page is fetched again, by [Cursor.Retry] or by Get after
+	// Validate or Version rejected the page, so middlewares accounting
+	// fetched pages can replace the last page instead of counting it
+	// twice. Middlewares setting it should call the Refetch of the
+	// config they wrap.
+	Refetch func()
 }
 
 // New creates a new instance of CursorIterator with the provided functions.
 func New[Input, Result any](
 	config Config[Input, Result],
 ) *Cursor[Input, Result] {
+	fetchFirst := config.fetchFirstContext()
 	return &Cursor[Input, Result]{
 		next:  true,
 		input: config.GetFirstInput(),
 
 		hasNext:       config.HasNext,
-		fetchNext:     config.FetchNext,
+		fetchNext:     config.fetchNextContext(),
+		fetchFirst:    fetchFirst,
+		fresh:         true,
+		pageContext:   config.PageContext,
 		getFirstInput: config.GetFirstInput,
+		start:         config.Start,
+		started:       config.Start == nil,
+		normalize:     config.Normalize,
+		validate:      config.Validate,
+		version:       config.Version,
+		name:          config.Name,
+		labels:        config.Labels,
+		redact:        config.Redact,
+		beforeRefetch: config.Refetch,
+	}
+}
+
+// onRefetch adds refetch to the functions called before the last page
+// is fetched again.
+func (c *Config[Input, Result]) onRefetch(refetch func()) {
+	if next := c.Refetch; next != nil {
+		c.Refetch = func() {
+			refetch()
+			next()
+		}
+		return
+	}
+	c.Refetch = refetch
+}
+
+// fetchNextContext returns FetchNextContext, or FetchNext adapted to
+// it.
+func (c *Config[Input, Result]) fetchNextContext() func(ctx context.Context, input Input) (Result, error) {
+	return withContext(c.FetchNextContext, c.FetchNext)
+}
+
+// fetchFirstContext returns FetchFirstContext, or FetchFirst adapted to
+// it. It is nil if neither is set.
+func (c *Config[Input, Result]) fetchFirstContext() func(ctx context.Context, input Input) (Result, error) {
+	return withContext(c.FetchFirstContext, c.FetchFirst)
+}
+
+func withContext[Input, Result any](
+	fetchContext func(ctx context.Context, input Input) (Result, error),
+	fetch func(input Input) (Result, error),
+) func(ctx context.Context, input Input) (Result, error) {
+	if fetchContext != nil || fetch == nil {
+		return fetchContext
+	}
+	return func(_ context.Context, input Input) (Result, error) {
+		return fetch(input)
 	}
 }
 
@@ -47,30 +179,186 @@ func (d *Cursor[Input, Result]) Next() bool {
 	return d.next
 }
 
+// Name returns the name of the cursor set in [Config].
+func (d *Cursor[Input, Result]) Name() string {
+	return d.name
+}
+
+// Labels returns labels of the cursor set in [Config].
+func (d *Cursor[Input, Result]) Labels() map[string]string {
+	return d.labels
+}
+
+// describe returns input for errors and logs, redacted if the cursor
+// has Redact.
+func (d *Cursor[Input, Result]) describe(input Input) any {
+	if d.redact == nil {
+		return input
+	}
+	return d.redact(input)
+}
+
+// Input returns the input that will be used to fetch the next Result.
+// It can be persisted and returned from GetFirstInput to resume the
+// iteration later.
+func (d *Cursor[Input, Result]) Input() Input {
+	return d.input
+}
+
 // Get returns the current element of the iterator and advances to the next element.
 // An error is returned if called when there are no more elements.
 func (d *Cursor[Input, Result]) Get() (Result, error) {
+	return d.GetContext(context.Background())
+}
+
+// GetContext works like [Cursor.Get], passing ctx to context-aware
+// functions of [Config]. If ctx is done, its error is returned without
+// fetching.
+func (d *Cursor[Input, Result]) GetContext(ctx context.Context) (Result, error) {
 	if !d.next {
 		return d.result, ErrStop
 	}
+	if err := ctx.Err(); err != nil {
+		return d.result, err
+	}
 
 	var err error
 
-	d.result, err = d.fetchNext(d.input)
+	if !d.started {
+		d.input, err = d.start(ctx, d.input)
+		if err != nil {
+			return d.result, err
+		}
+		d.started = true
+	}
+
+	fetch := d.fetchNext
+	if d.fresh && d.fetchFirst != nil {
+		fetch = d.fetchFirst
+	}
+	if d.rejected {
+		d.notifyRefetch()
+	}
+	var fetched bool
+	d.result, fetched, err = d.fetch(ctx, fetch, d.page, d.input)
 	if err != nil {
+		d.rejected = fetched
 		return d.result, err
 	}
+	d.rejected = false
+	d.fresh = false
+	d.last, d.refetch = d.input, fetch
+	d.page++
 
 	d.input, d.next = d.hasNext(d.result)
 	return d.result, nil
 }
 
+// Retry fetches the Result returned by the last Get again with the
+// same input, without advancing the cursor, so a page whose processing
+// failed can be retried without restarting the iteration. ErrNoPage is
+// returned if Get did not return a Result since the cursor was created,
+// reset or restored.
+func (d *Cursor[Input, Result]) Retry() (Result, error) {
+	return d.RetryContext(context.Background())
+}
+
+// RetryContext works like [Cursor.Retry], passing ctx to context-aware
+// functions of [Config].
+func (d *Cursor[Input, Result]) RetryContext(ctx context.Context) (Result, error) {
+	if d.refetch == nil {
+		return d.result, ErrNoPage
+	}
+	if err := ctx.Err(); err != nil {
+		return d.result, err
+	}
+
+	d.notifyRefetch()
+	result, _, err := d.fetch(ctx, d.refetch, d.page-1, d.last)
+	if err != nil {
+		return result, err
+	}
+	d.result = result
+	return result, nil
+}
+
+func (d *Cursor[Input, Result]) notifyRefetch() {
+	if d.beforeRefetch != nil {
+		d.beforeRefetch()
+	}
+}
+
+// fetch fetches, normalizes and checks a page. Fetched reports whether
+// the fetch succeeded, even if the page was rejected.
+func (d *Cursor[Input, Result]) fetch(
+	ctx context.Context,
+	fetch func(ctx context.Context, input Input) (Result, error),
+	page int,
+	input Input,
+) (result Result, fetched bool, err error) {
+	if d.pageContext != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = d.pageContext(ctx, page, input)
+		defer cancel()
+	}
+
+	result, err = fetch(ctx, input)
+	if err != nil {
+		return result, false, err
+	}
+
+	if d.normalize != nil {
+		result = d.normalize(result)
+	}
+
+	if d.validate != nil {
+		if err := d.validate(result); err != nil {
+			return result, true, fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+		}
+	}
+
+	return result, true, d.checkSnapshot(result)
+}
+
+func (d *Cursor[Input, Result]) checkSnapshot(result Result) error {
+	if d.version == nil {
+		return nil
+	}
+
+	version := d.version(result)
+	if version == "" {
+		return nil
+	}
+	if d.snapshot == "" {
+		d.snapshot = version
+		return nil
+	}
+	if version != d.snapshot {
+		return fmt.Errorf("%w: %q != %q", ErrSnapshotChanged, version, d.snapshot)
+	}
+
+	return nil
+}
+
 // Iterate iterates over the elements using the provided callback function.
 // It stops iterating if the callback function returns the ErrStop sentinel error.
 // Any other error returned by the callback function will be propagated.
 func (d *Cursor[Input, Result]) Iterate(callback func(response Result) error) error {
-	for d.Next() {
-		response, err := d.Get()
+	return iterate(d.Next, d.Get, callback)
+}
+
+// IterateContext works like [Cursor.Iterate], passing ctx to
+// context-aware functions of [Config]. Iteration stops with the error
+// of ctx once it is done.
+func (d *Cursor[Input, Result]) IterateContext(ctx context.Context, callback func(response Result) error) error {
+	return iterate(d.Next, func() (Result, error) {
+		return d.GetContext(ctx)
+	}, callback)
+}
+
+func iterate[T any](next func() bool, get func() (T, error), callback func(value T) error) error {
+	for next() {
+		value, err := get()
 		if err != nil {
 			if errors.Is(err, ErrStop) {
 				return nil
@@ -78,7 +366,7 @@ func (d *Cursor[Input, Result]) Iterate(callback func(response Result) error) er
 			return err
 		}
 
-		if err := callback(response); err != nil {
+		if err := callback(value); err != nil {
 			if errors.Is(err, ErrStop) {
 				return nil
 			}
@@ -93,4 +381,10 @@ func (d *Cursor[Input, Result]) Iterate(callback func(response Result) error) er
 func (d *Cursor[Input, Result]) Reset() {
 	d.input = d.getFirstInput()
 	d.next = true
+	d.started = d.start == nil
+	d.fresh = true
+	d.page = 0
+	d.refetch = nil
+	d.rejected = false
+	d.snapshot = ""
 }
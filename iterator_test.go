@@ -2,16 +2,19 @@ package iter_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
 	"go.teddydd.me/iter"
+	"go.teddydd.me/iter/itertest"
 )
 
 type Record struct {
@@ -19,91 +22,64 @@ type Record struct {
 }
 
 func MockAPIHandler(recordsCount int) http.Handler {
+	return itertest.NewHandler(itertest.ServerConfig{Records: recordsCount})
+}
+
+func brokenServerHandler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Parse the request body
-		var requestBody struct {
+		http.Error(w, "something went wrong", http.StatusInternalServerError)
+	})
+}
+
+func nextRecord(result []Record) (int, bool) {
+	if len(result) > 0 {
+		// Use the last record ID as the cursor value
+		return result[len(result)-1].ID, true
+	}
+	// No more records available
+	return 0, false
+}
+
+func fetchRecords(mockServer *httptest.Server) func(input int) ([]Record, error) {
+	return func(input int) ([]Record, error) {
+		// Send a request to the mock API server with the lastSeen cursor value
+		reqBody, err := json.Marshal(struct {
 			LastSeen int `json:"lastSeen"`
 			Limit    int `json:"limit"`
-		}
-		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
+		}{
+			LastSeen: input,
+			Limit:    2, // Specify the desired limit
+		})
+		if err != nil {
+			return nil, err
 		}
 
-		// Simulate fetching records based on the lastSeen value
-		records := make([]Record, recordsCount)
-		for i := 0; i < recordsCount; i++ {
-			records[i] = Record{ID: i + 1}
+		resp, err := http.Post(mockServer.URL, "application/json", bytes.NewReader(reqBody))
+		if err != nil {
+			return nil, err
 		}
+		defer resp.Body.Close()
 
-		// Filter the records based on the lastSeen value and limit
-		filteredRecords := make([]Record, 0, requestBody.Limit)
-		for _, record := range records {
-			if record.ID > requestBody.LastSeen {
-				filteredRecords = append(filteredRecords, record)
-				if len(filteredRecords) == requestBody.Limit {
-					break
-				}
-			}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 		}
 
-		// Write the response
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(filteredRecords); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		// Parse the response body
+		var records []Record
+		if err := json.NewDecoder(resp.Body).Decode(&records); err != nil {
+			return nil, err
 		}
-	})
-}
 
-func brokenServerHandler() http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		http.Error(w, "something went wrong", http.StatusInternalServerError)
-	})
+		// No need to check if more records available here, since HasNext handles it.
+
+		return records, nil
+	}
 }
 
 func simpleIterator(mockServer *httptest.Server) *iter.Cursor[int, []Record] {
 	return iter.New[int, []Record](iter.Config[int, []Record]{
-		HasNext: func(result []Record) (int, bool) {
-			if len(result) > 0 {
-				// Use the last record ID as the cursor value
-				return result[len(result)-1].ID, true
-			}
-			// No more records available
-			return 0, false
-		},
-		FetchNext: func(input int) ([]Record, error) {
-			// Send a request to the mock API server with the lastSeen cursor value
-			reqBody, err := json.Marshal(struct {
-				LastSeen int `json:"lastSeen"`
-				Limit    int `json:"limit"`
-			}{
-				LastSeen: input,
-				Limit:    2, // Specify the desired limit
-			})
-			if err != nil {
-				return nil, err
-			}
-
-			resp, err := http.Post(mockServer.URL, "application/json", bytes.NewReader(reqBody))
-			if err != nil {
-				return nil, err
-			}
-			defer resp.Body.Close()
-
-			if resp.StatusCode != http.StatusOK {
-				return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-			}
-
-			// Parse the response body
-			var records []Record
-			if err := json.NewDecoder(resp.Body).Decode(&records); err != nil {
-				return nil, err
-			}
-
-			// No need to check if more records available here, since HasNext handles it.
-
-			return records, nil
-		},
+		HasNext:   nextRecord,
+		FetchNext: fetchRecords(mockServer),
 		GetFirstInput: func() int {
 			// Return an initial cursor value
 			return 0
@@ -274,3 +250,304 @@ func TestUnexpectedServerError(t *testing.T) {
 		t.Fatalf("error should be unexpected status code but got %+v", err2)
 	}
 }
+
+func TestSnapshotChanged(t *testing.T) {
+	type page struct {
+		version string
+		next    int
+	}
+	versions := []string{"v1", "", "v1", "v2"}
+	iterator := iter.New(iter.Config[int, page]{
+		HasNext: func(result page) (int, bool) {
+			return result.next, result.next < len(versions)
+		},
+		FetchNext: func(input int) (page, error) {
+			return page{version: versions[input], next: input + 1}, nil
+		},
+		GetFirstInput: func() int {
+			return 0
+		},
+		Version: func(result page) string {
+			return result.version
+		},
+	})
+
+	pages := 0
+	err := iterator.Iterate(func(response page) error {
+		pages++
+		return nil
+	})
+	if !errors.Is(err, iter.ErrSnapshotChanged) {
+		t.Fatalf("expected ErrSnapshotChanged, got %+v", err)
+	}
+	if pages != 3 {
+		t.Errorf("expected 3 pages before snapshot change, got %d", pages)
+	}
+
+	versions[3] = "v1"
+	iterator.Reset()
+	if err := iterator.Iterate(func(response page) error { return nil }); err != nil {
+		t.Errorf("expected no error after reset, got %+v", err)
+	}
+}
+
+func TestStart(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+	var starts []int
+	config := pagesConfig([][]int{{1}, {2}, {3}})
+	config.Start = func(ctx context.Context, input int) (int, error) {
+		starts = append(starts, input)
+		if len(starts) == 1 {
+			return 0, errUnavailable
+		}
+		// the API hands out scroll starting at the second page
+		return input + 1, nil
+	}
+	cursor := iter.New(config)
+
+	if _, err := cursor.Get(); !errors.Is(err, errUnavailable) {
+		t.Fatalf("expected start error, got %v", err)
+	}
+	values, err := iter.Items(cursor).Collect()
+	if err != nil || !reflect.DeepEqual(values, []int{2, 3}) {
+		t.Fatalf("unexpected values: %v, %v", values, err)
+	}
+
+	cursor.Reset()
+	if _, err := cursor.Collect(); err != nil {
+		t.Fatalf("unexpected error after reset: %v", err)
+	}
+	if !reflect.DeepEqual(starts, []int{0, 0, 0}) {
+		t.Errorf("unexpected Start calls: %v", starts)
+	}
+}
+
+func TestFetchFirst(t *testing.T) {
+	errHandshake := errors.New("handshake")
+	var calls []string
+	config := pagesConfig([][]int{{1}, {2}, {3}})
+	fetchNext := config.FetchNext
+	config.FetchNext = func(input int) ([]int, error) {
+		calls = append(calls, "next")
+		return fetchNext(input)
+	}
+	config.FetchFirst = func(input int) ([]int, error) {
+		calls = append(calls, "first")
+		if len(calls) == 1 {
+			return nil, errHandshake
+		}
+		return fetchNext(input)
+	}
+	fetches := 0
+	cursor := iter.New(iter.Use(config, iter.WrapFetch(func(fetch func(input int) ([]int, error)) func(input int) ([]int, error) {
+		return func(input int) ([]int, error) {
+			fetches++
+			return fetch(input)
+		}
+	})))
+
+	if _, err := cursor.Get(); !errors.Is(err, errHandshake) {
+		t.Fatalf("expected handshake error, got %v", err)
+	}
+	values, err := iter.Items(cursor).Collect()
+	if err != nil || !reflect.DeepEqual(values, []int{1, 2, 3}) {
+		t.Fatalf("unexpected values: %v, %v", values, err)
+	}
+
+	cursor.Reset()
+	if _, err := cursor.Get(); err != nil {
+		t.Fatalf("unexpected error after reset: %v", err)
+	}
+	expected := []string{"first", "first", "next", "next", "first"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+	if fetches != len(expected) {
+		t.Errorf("middleware saw %d fetches, want %d", fetches, len(expected))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	errEmpty := errors.New("empty page")
+	config := pagesConfig([][]int{{1}, {}, {3}})
+	config.Validate = func(result []int) error {
+		if len(result) == 0 {
+			return errEmpty
+		}
+		return nil
+	}
+
+	var values []int
+	err := iter.New(config).Iterate(func(page []int) error {
+		values = append(values, page...)
+		return nil
+	})
+	if !errors.Is(err, iter.ErrInvalidResponse) || !errors.Is(err, errEmpty) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if !reflect.DeepEqual(values, []int{1}) {
+		t.Errorf("unexpected values: %v", values)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	config := pagesConfig([][]int{{3, 1, 2}, {5, 4}})
+	hasNext := config.HasNext
+	config.HasNext = func(result []int) (int, bool) {
+		if !sort.IntsAreSorted(result) {
+			t.Errorf("HasNext got page before Normalize: %v", result)
+		}
+		return hasNext(result)
+	}
+	config.Normalize = func(result []int) []int {
+		sorted := append([]int(nil), result...)
+		sort.Ints(sorted)
+		return sorted
+	}
+
+	values, err := iter.Items(iter.New(config)).Collect()
+	if err != nil || !reflect.DeepEqual(values, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("unexpected values: %v, %v", values, err)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var fetched []int
+	config := pagesConfig([][]int{{1}, {2}, {3}})
+	fetchNext := config.FetchNext
+	config.FetchNext = func(input int) ([]int, error) {
+		fetched = append(fetched, input)
+		return fetchNext(input)
+	}
+	cursor := iter.New(config)
+
+	if _, err := cursor.Retry(); !errors.Is(err, iter.ErrNoPage) {
+		t.Fatalf("expected ErrNoPage, got %v", err)
+	}
+
+	var values []int
+	for cursor.Next() {
+		page, err := cursor.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page[0] == 2 {
+			if page, err = cursor.Retry(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		values = append(values, page...)
+	}
+
+	if !reflect.DeepEqual(values, []int{1, 2, 3}) {
+		t.Errorf("unexpected values: %v", values)
+	}
+	if !reflect.DeepEqual(fetched, []int{0, 1, 1, 2}) {
+		t.Errorf("unexpected fetched inputs: %v", fetched)
+	}
+}
+
+func TestPageContext(t *testing.T) {
+	type key struct{}
+	pages := [][]int{{1}, {2}, {3}}
+	config := pagesConfig(pages)
+	fetchNext := config.FetchNext
+	config.FetchNext = nil
+	config.FetchNextContext = func(ctx context.Context, input int) ([]int, error) {
+		if page := ctx.Value(key{}); page != input {
+			t.Errorf("unexpected page of input %d: %v", input, page)
+		}
+		return fetchNext(input)
+	}
+	canceled := 0
+	config.PageContext = func(ctx context.Context, page int, input int) (context.Context, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.WithValue(ctx, key{}, page))
+		return ctx, func() {
+			canceled++
+			cancel()
+		}
+	}
+	cursor := iter.New(config)
+
+	if _, err := cursor.CollectN(2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if canceled != 2 {
+		t.Errorf("expected page contexts to be canceled, got %d", canceled)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := cursor.GetContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if cursor.Input() != 2 {
+		t.Errorf("canceled Get advanced the cursor to %d", cursor.Input())
+	}
+	err := cursor.IterateContext(context.Background(), func([]int) error { return nil })
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// pagesConfig returns config iterating over in-memory pages.
+func pagesConfig[T any](pages [][]T) iter.Config[int, []T] {
+	var next int
+	return iter.Config[int, []T]{
+		HasNext: func(result []T) (int, bool) {
+			return next, next < len(pages)
+		},
+		FetchNext: func(input int) ([]T, error) {
+			next = input + 1
+			return pages[input], nil
+		},
+		GetFirstInput: func() int {
+			return 0
+		},
+	}
+}
+
+func TestRefetch(t *testing.T) {
+	var refetched int
+	rejected := false
+	config := pagesConfig([][]int{{1}, {2}})
+	config.Validate = func(result []int) error {
+		if result[0] == 2 && !rejected {
+			rejected = true
+			return errors.New("transient")
+		}
+		return nil
+	}
+	config.Refetch = func() { refetched++ }
+	cursor := iter.New(config)
+
+	if _, err := cursor.Get(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cursor.Retry(); err != nil || refetched != 1 {
+		t.Fatalf("expected refetch before retry, got %d, %v", refetched, err)
+	}
+	if _, err := cursor.Get(); !errors.Is(err, iter.ErrInvalidResponse) || refetched != 1 {
+		t.Fatalf("expected rejected page without refetch, got %d, %v", refetched, err)
+	}
+	if page, err := cursor.Get(); err != nil || page[0] != 2 || refetched != 2 {
+		t.Errorf("expected refetch of rejected page, got %v, %d, %v", page, refetched, err)
+	}
+}
+
+// collectRetrying collects pages of cursor, fetching every page twice
+// with Retry.
+func collectRetrying[T any](cursor *iter.Cursor[int, []T]) ([][]T, error) {
+	var pages [][]T
+	for cursor.Next() {
+		if _, err := cursor.Get(); err != nil {
+			return pages, err
+		}
+		page, err := cursor.Retry()
+		if err != nil {
+			return pages, err
+		}
+		pages = append(pages, page)
+	}
+	return pages, nil
+}
@@ -0,0 +1,42 @@
+package itertest_test
+
+import (
+	"testing"
+	"time"
+
+	"go.teddydd.me/iter"
+	"go.teddydd.me/iter/itertest"
+)
+
+var _ iter.Clock = (*itertest.Clock)(nil)
+
+func TestClock(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := itertest.NewClock(start)
+
+	done := make(chan struct{})
+	go func() {
+		clock.Sleep(time.Minute)
+		close(done)
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Second)
+	select {
+	case <-done:
+		t.Fatal("woke up too early")
+	default:
+	}
+
+	clock.Advance(30 * time.Second)
+	<-done
+
+	if !clock.Now().Equal(start.Add(time.Minute)) {
+		t.Errorf("unexpected time: %v", clock.Now())
+	}
+	select {
+	case <-clock.After(0):
+	default:
+		t.Error("expected After(0) to fire immediately")
+	}
+}
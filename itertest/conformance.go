@@ -0,0 +1,185 @@
+package itertest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+// maxPages guards the conformance tests against cursors that never end.
+const maxPages = 10000
+
+// CursorFactory creates a fresh cursor over the same data. If failFirst
+// is true, the first fetch of the cursor must fail with ErrInjected, e.g.
+// by using [FailFirst] or [FailFirstClient].
+type CursorFactory[Input, Result any] func(failFirst bool) *iter.Cursor[Input, Result]
+
+// RunCursorTests checks that cursors created by factory follow the
+// [iter.Cursor] contract: Next is true before the first Get, Get returns
+// ErrStop once the cursor is depleted, Reset and Restore repeat the
+// iteration, fetch errors are returned without advancing the cursor,
+// and canceled contexts stop the iteration. The cursors must yield at
+// least one result, and the same results every time.
+func RunCursorTests[Input, Result any](t *testing.T, factory CursorFactory[Input, Result]) {
+	t.Helper()
+
+	expected := drain(t, factory(false))
+	if len(expected) == 0 {
+		t.Fatal("cursor yielded no results")
+	}
+
+	t.Run("first next", func(t *testing.T) {
+		if !factory(false).Next() {
+			t.Error("Next is false before the first Get")
+		}
+	})
+
+	t.Run("depletion", func(t *testing.T) {
+		cursor := factory(false)
+		drain(t, cursor)
+		if cursor.Next() {
+			t.Error("Next is true after depletion")
+		}
+		if _, err := cursor.Get(); !errors.Is(err, iter.ErrStop) {
+			t.Errorf("expected ErrStop after depletion, got %v", err)
+		}
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		cursor := factory(false)
+		drain(t, cursor)
+		cursor.Reset()
+		if results := drain(t, cursor); !reflect.DeepEqual(results, expected) {
+			t.Errorf("results after reset differ: got %+v, want %+v", results, expected)
+		}
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		cursor := factory(false)
+		if _, err := cursor.Get(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		restored := factory(false)
+		restored.Restore(cursor.Checkpoint())
+		results, want := drain(t, restored), expected[1:]
+		if len(results) != len(want) || (len(want) > 0 && !reflect.DeepEqual(results, want)) {
+			t.Errorf("results after restore differ: got %+v, want %+v", results, want)
+		}
+	})
+
+	t.Run("error propagation", func(t *testing.T) {
+		cursor := factory(true)
+		input := cursor.Input()
+		if _, err := cursor.Get(); !errors.Is(err, ErrInjected) {
+			t.Fatalf("expected injected error, got %v", err)
+		}
+		if !cursor.Next() || !reflect.DeepEqual(cursor.Input(), input) {
+			t.Fatalf("cursor advanced after error")
+		}
+
+		result, err := cursor.Get()
+		if err != nil {
+			t.Fatalf("unexpected error on retry: %v", err)
+		}
+		if !reflect.DeepEqual(result, expected[0]) {
+			t.Errorf("unexpected result on retry: got %+v, want %+v", result, expected[0])
+		}
+
+		err = factory(true).Iterate(func(Result) error { return nil })
+		if !errors.Is(err, ErrInjected) {
+			t.Errorf("expected Iterate to return injected error, got %v", err)
+		}
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		cursor := factory(false)
+		input := cursor.Input()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := cursor.GetContext(ctx); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if !cursor.Next() || !reflect.DeepEqual(cursor.Input(), input) {
+			t.Fatalf("cursor advanced after cancellation")
+		}
+
+		ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+		var results []Result
+		err := cursor.IterateContext(ctx, func(result Result) error {
+			results = append(results, result)
+			cancel()
+			return nil
+		})
+		if len(expected) > 1 && !errors.Is(err, context.Canceled) {
+			t.Errorf("expected IterateContext to return context.Canceled, got %v", err)
+		}
+		if len(results) != 1 || !reflect.DeepEqual(results[0], expected[0]) {
+			t.Errorf("unexpected results before cancellation: got %+v, want %+v", results, expected[:1])
+		}
+	})
+}
+
+func drain[Input, Result any](t *testing.T, cursor *iter.Cursor[Input, Result]) []Result {
+	t.Helper()
+
+	var results []Result
+	for cursor.Next() {
+		if len(results) == maxPages {
+			t.Fatalf("cursor did not end after %d results", maxPages)
+		}
+		result, err := cursor.Get()
+		if errors.Is(err, iter.ErrStop) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// FailFirst returns middleware failing the first fetch with
+// ErrInjected.
+func FailFirst[Input, Result any]() iter.Middleware[Input, Result] {
+	failed := false
+	return iter.WrapFetch(func(fetchNext func(input Input) (Result, error)) func(input Input) (Result, error) {
+		return func(input Input) (Result, error) {
+			if !failed {
+				failed = true
+				var zero Result
+				return zero, ErrInjected
+			}
+			return fetchNext(input)
+		}
+	})
+}
+
+// FailFirstClient returns client failing the first request with
+// ErrInjected, for HTTP based adapters. http.DefaultClient is used if
+// client is nil.
+func FailFirstClient(client iter.Doer) iter.Doer {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &failFirstClient{client: client}
+}
+
+type failFirstClient struct {
+	client iter.Doer
+	failed atomic.Bool
+}
+
+func (c *failFirstClient) Do(req *http.Request) (*http.Response, error) {
+	if c.failed.CompareAndSwap(false, true) {
+		return nil, ErrInjected
+	}
+	return c.client.Do(req)
+}
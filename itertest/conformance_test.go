@@ -0,0 +1,33 @@
+package itertest_test
+
+import (
+	"testing"
+
+	"go.teddydd.me/iter"
+	"go.teddydd.me/iter/itertest"
+)
+
+func TestRunCursorTests(t *testing.T) {
+	t.Run("config", func(t *testing.T) {
+		itertest.RunCursorTests(t, func(failFirst bool) *iter.Cursor[int, []int] {
+			config := pages(3)
+			if failFirst {
+				config = iter.Use(config, itertest.FailFirst[int, []int]())
+			}
+			return iter.New(config)
+		})
+	})
+
+	t.Run("http", func(t *testing.T) {
+		server := itertest.NewServer(itertest.ServerConfig{Records: 25, Style: itertest.Link})
+		defer server.Close()
+
+		itertest.RunCursorTests(t, func(failFirst bool) *iter.Cursor[string, []itertest.Record] {
+			config := iter.HTTPConfig[[]itertest.Record]{URL: server.URL}
+			if failFirst {
+				config.Client = itertest.FailFirstClient(nil)
+			}
+			return iter.NewHTTP(config)
+		})
+	})
+}
@@ -0,0 +1,92 @@
+package itertest
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"go.teddydd.me/iter"
+)
+
+// ErrInjected is returned by fetches failed by [Faults] without
+// configured error.
+var ErrInjected = errors.New("injected fault")
+
+// Faults configures faults injected into fetches of a cursor. Faults
+// are chosen by a random generator seeded with Seed, so every run with
+// the same seed fails the same way.
+type Faults[Input, Result any] struct {
+	Seed int64
+	// ErrorEvery fails every Nth fetch, zero disables it.
+	ErrorEvery int
+	// ErrorRate is the probability that a fetch fails.
+	ErrorRate float64
+	// Err is returned by failed fetches, ErrInjected if nil.
+	Err error
+	// LatencyRate is the probability that a fetch is delayed by Latency.
+	LatencyRate float64
+	Latency     time.Duration
+	// Clock is used to delay fetches, iter.SystemClock if nil.
+	Clock iter.Clock
+	// TruncateRate is the probability that a result is replaced by the
+	// one returned from Truncate, e.g. [TruncateSlice].
+	TruncateRate float64
+	Truncate     func(result Result, rng *rand.Rand) Result
+}
+
+// Middleware returns a middleware injecting the faults. The fault
+// sequence starts over when the cursor is reset.
+func (f Faults[Input, Result]) Middleware() iter.Middleware[Input, Result] {
+	return func(config iter.Config[Input, Result]) iter.Config[Input, Result] {
+		var (
+			rng     *rand.Rand
+			fetches int
+		)
+
+		clock := f.Clock
+		if clock == nil {
+			clock = iter.SystemClock
+		}
+		fault := f.Err
+		if fault == nil {
+			fault = ErrInjected
+		}
+
+		getFirstInput := config.GetFirstInput
+		config.GetFirstInput = func() Input {
+			rng = rand.New(rand.NewSource(f.Seed))
+			fetches = 0
+			return getFirstInput()
+		}
+
+		return iter.WrapFetch(func(fetchNext func(input Input) (Result, error)) func(input Input) (Result, error) {
+			return func(input Input) (Result, error) {
+				fetches++
+
+				if f.LatencyRate > 0 && rng.Float64() < f.LatencyRate {
+					clock.Sleep(f.Latency)
+				}
+
+				failed := f.ErrorEvery > 0 && fetches%f.ErrorEvery == 0
+				if f.ErrorRate > 0 && rng.Float64() < f.ErrorRate {
+					failed = true
+				}
+				if failed {
+					var zero Result
+					return zero, fault
+				}
+
+				result, err := fetchNext(input)
+				if err == nil && f.Truncate != nil && f.TruncateRate > 0 && rng.Float64() < f.TruncateRate {
+					result = f.Truncate(result, rng)
+				}
+				return result, err
+			}
+		})(config)
+	}
+}
+
+// TruncateSlice drops a random number of trailing items.
+func TruncateSlice[T any](result []T, rng *rand.Rand) []T {
+	return result[:rng.Intn(len(result)+1)]
+}
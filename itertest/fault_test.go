@@ -0,0 +1,98 @@
+package itertest_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.teddydd.me/iter"
+	"go.teddydd.me/iter/itertest"
+)
+
+func pages(n int) iter.Config[int, []int] {
+	var next int
+	return iter.Config[int, []int]{
+		HasNext: func(result []int) (int, bool) {
+			return next, next < n
+		},
+		FetchNext: func(input int) ([]int, error) {
+			next = input + 1
+			return []int{input, input, input}, nil
+		},
+		GetFirstInput: func() int {
+			return 0
+		},
+	}
+}
+
+func fetchAll(iterator *iter.Cursor[int, []int]) ([][]int, []error) {
+	var (
+		results [][]int
+		errs    []error
+	)
+	for iterator.Next() {
+		result, err := iterator.Get()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, errs
+}
+
+func TestFaultsErrorEvery(t *testing.T) {
+	faults := itertest.Faults[int, []int]{ErrorEvery: 2}
+	iterator := iter.New(iter.Use(pages(3), faults.Middleware()))
+
+	results, errs := fetchAll(iterator)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	for _, err := range errs {
+		if !errors.Is(err, itertest.ErrInjected) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if len(results) != 3 {
+		t.Errorf("expected all pages to be fetched after retries, got %v", results)
+	}
+}
+
+func TestFaultsDeterministic(t *testing.T) {
+	faults := itertest.Faults[int, []int]{
+		Seed:         42,
+		ErrorRate:    0.3,
+		TruncateRate: 0.5,
+		Truncate:     itertest.TruncateSlice[int],
+	}
+	iterator := iter.New(iter.Use(pages(20), faults.Middleware()))
+
+	results, errs := fetchAll(iterator)
+	iterator.Reset()
+	again, againErrs := fetchAll(iterator)
+
+	if !reflect.DeepEqual(results, again) || len(errs) != len(againErrs) {
+		t.Errorf("expected the same faults after reset")
+	}
+	if len(errs) == 0 {
+		t.Errorf("expected some errors")
+	}
+}
+
+func TestFaultsLatency(t *testing.T) {
+	clock := itertest.NewClock(time.Now())
+	faults := itertest.Faults[int, []int]{LatencyRate: 1, Latency: time.Second, Clock: clock}
+	iterator := iter.New(iter.Use(pages(1), faults.Middleware()))
+
+	done := make(chan struct{})
+	go func() {
+		iterator.Get()
+		close(done)
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	<-done
+}
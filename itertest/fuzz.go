@@ -0,0 +1,60 @@
+package itertest
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+// CheckpointRoundTrip is meant to be called from fuzz targets with
+// arbitrary data. It checks that checkpoints of given Input type either
+// fail to unmarshal, or unmarshal into a value surviving a marshal and
+// unmarshal round trip.
+func CheckpointRoundTrip[Input any](t *testing.T, data []byte) {
+	t.Helper()
+
+	var checkpoint iter.Checkpoint[Input]
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return
+	}
+
+	encoded, err := json.Marshal(checkpoint)
+	if err != nil {
+		t.Fatalf("marshal checkpoint %+v: %v", checkpoint, err)
+	}
+
+	var decoded iter.Checkpoint[Input]
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("unmarshal checkpoint %s: %v", encoded, err)
+	}
+	if !reflect.DeepEqual(checkpoint, decoded) {
+		t.Fatalf("checkpoint changed in round trip: %+v != %+v", checkpoint, decoded)
+	}
+}
+
+// CodecRoundTrip is meant to be called from fuzz targets with arbitrary
+// data. It checks that the codec either rejects the data, or decodes it
+// into input surviving an encode and decode round trip.
+func CodecRoundTrip[Input any](t *testing.T, codec iter.Codec[Input], data []byte) {
+	t.Helper()
+
+	input, err := codec.Unmarshal(data)
+	if err != nil {
+		return
+	}
+
+	encoded, err := codec.Marshal(input)
+	if err != nil {
+		t.Fatalf("marshal input %+v: %v", input, err)
+	}
+
+	decoded, err := codec.Unmarshal(encoded)
+	if err != nil {
+		t.Fatalf("unmarshal input %q: %v", encoded, err)
+	}
+	if !reflect.DeepEqual(input, decoded) {
+		t.Fatalf("input changed in round trip: %+v != %+v", input, decoded)
+	}
+}
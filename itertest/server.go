@@ -0,0 +1,171 @@
+package itertest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.teddydd.me/iter"
+)
+
+// TokenStyle selects how [NewServer] paginates records.
+type TokenStyle int
+
+const (
+	// LastSeen expects POST requests with JSON body
+	// {"lastSeen": id, "limit": n} and responds with an array of records
+	// with IDs greater than lastSeen. An empty array ends the listing.
+	LastSeen TokenStyle = iota
+	// Offset expects GET requests with offset and limit query parameters
+	// and responds with {"records": [...], "total": n}.
+	Offset
+	// PageToken expects GET requests with page_token query parameter and
+	// responds with {"records": [...], "next_page_token": "..."}. Tokens
+	// are opaque and the last page has an empty one.
+	PageToken
+	// Link expects GET requests with page query parameter, starting at 1,
+	// and responds with an array of records. Following pages are linked
+	// by Link header with rel="next".
+	Link
+)
+
+// Record is served by [NewServer].
+type Record struct {
+	ID int `json:"id"`
+}
+
+// ServerConfig configures a fake paginated API.
+type ServerConfig struct {
+	// Records is the number of records, with IDs starting at 1.
+	Records int
+	// PageSize is the number of records per page, 10 by default. Limit
+	// sent by the client takes precedence.
+	PageSize int
+	Style    TokenStyle
+	// FailEvery responds with 500 Internal Server Error to every Nth
+	// request, zero disables it.
+	FailEvery int
+	// Latency delays every response.
+	Latency time.Duration
+	// Clock is used for latency, iter.SystemClock if nil.
+	Clock iter.Clock
+}
+
+// NewHandler returns a handler of a fake paginated API.
+func NewHandler(config ServerConfig) http.Handler {
+	var (
+		mu       sync.Mutex
+		requests int
+	)
+
+	pageSize := config.PageSize
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	clock := config.Clock
+	if clock == nil {
+		clock = iter.SystemClock
+	}
+
+	page := func(offset, limit int) []Record {
+		records := make([]Record, 0, limit)
+		for id := offset + 1; id <= config.Records && len(records) < limit; id++ {
+			records = append(records, Record{ID: id})
+		}
+		return records
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		failed := config.FailEvery > 0 && requests%config.FailEvery == 0
+		mu.Unlock()
+
+		if config.Latency > 0 {
+			clock.Sleep(config.Latency)
+		}
+		if failed {
+			http.Error(w, "injected failure", http.StatusInternalServerError)
+			return
+		}
+
+		limit := pageSize
+		if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+			limit = l
+		}
+
+		var response any
+		switch config.Style {
+		case LastSeen:
+			var request struct {
+				LastSeen int `json:"lastSeen"`
+				Limit    int `json:"limit"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if request.Limit > 0 {
+				limit = request.Limit
+			}
+			response = page(request.LastSeen, limit)
+
+		case Offset:
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			response = struct {
+				Records []Record `json:"records"`
+				Total   int      `json:"total"`
+			}{page(offset, limit), config.Records}
+
+		case PageToken:
+			offset := 0
+			if token := r.URL.Query().Get("page_token"); token != "" {
+				data, err := base64.RawURLEncoding.DecodeString(token)
+				if err == nil {
+					offset, err = strconv.Atoi(string(data))
+				}
+				if err != nil {
+					http.Error(w, "invalid page token", http.StatusBadRequest)
+					return
+				}
+			}
+
+			var next string
+			if offset+limit < config.Records {
+				next = base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset + limit)))
+			}
+			response = struct {
+				Records       []Record `json:"records"`
+				NextPageToken string   `json:"next_page_token"`
+			}{page(offset, limit), next}
+
+		case Link:
+			n, err := strconv.Atoi(r.URL.Query().Get("page"))
+			if err != nil || n < 1 {
+				n = 1
+			}
+			if n*limit < config.Records {
+				query := r.URL.Query()
+				query.Set("page", strconv.Itoa(n+1))
+				w.Header().Set("Link", fmt.Sprintf(`<%s?%s>; rel="next"`, r.URL.Path, query.Encode()))
+			}
+			response = page((n-1)*limit, limit)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// NewServer starts a fake paginated API. The caller should call Close
+// when finished.
+func NewServer(config ServerConfig) *httptest.Server {
+	return httptest.NewServer(NewHandler(config))
+}
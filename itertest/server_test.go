@@ -0,0 +1,159 @@
+package itertest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"go.teddydd.me/iter"
+	"go.teddydd.me/iter/itertest"
+)
+
+func get(t *testing.T, url string, v any) http.Header {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Header
+}
+
+func checkIDs(t *testing.T, records []itertest.Record, count int) {
+	t.Helper()
+
+	if len(records) != count {
+		t.Fatalf("expected %d records, got %d", count, len(records))
+	}
+	for i, record := range records {
+		if record.ID != i+1 {
+			t.Fatalf("unexpected record %d: %+v", i, record)
+		}
+	}
+}
+
+func TestServerLastSeen(t *testing.T) {
+	server := itertest.NewServer(itertest.ServerConfig{Records: 5})
+	defer server.Close()
+
+	iterator := iter.New(iter.Config[int, []itertest.Record]{
+		HasNext: func(result []itertest.Record) (int, bool) {
+			if len(result) == 0 {
+				return 0, false
+			}
+			return result[len(result)-1].ID, true
+		},
+		FetchNext: func(input int) ([]itertest.Record, error) {
+			body := fmt.Sprintf(`{"lastSeen":%d,"limit":2}`, input)
+			resp, err := http.Post(server.URL, "application/json", bytes.NewBufferString(body))
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+
+			var records []itertest.Record
+			err = json.NewDecoder(resp.Body).Decode(&records)
+			return records, err
+		},
+		GetFirstInput: func() int {
+			return 0
+		},
+	})
+
+	var records []itertest.Record
+	err := iterator.Iterate(func(response []itertest.Record) error {
+		records = append(records, response...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkIDs(t, records, 5)
+}
+
+func TestServerOffset(t *testing.T) {
+	server := itertest.NewServer(itertest.ServerConfig{Records: 25, Style: itertest.Offset})
+	defer server.Close()
+
+	var records []itertest.Record
+	for offset := 0; ; {
+		var page struct {
+			Records []itertest.Record
+			Total   int
+		}
+		get(t, fmt.Sprintf("%s?offset=%d", server.URL, offset), &page)
+		records = append(records, page.Records...)
+		offset += len(page.Records)
+		if offset >= page.Total {
+			break
+		}
+	}
+	checkIDs(t, records, 25)
+}
+
+func TestServerPageToken(t *testing.T) {
+	server := itertest.NewServer(itertest.ServerConfig{Records: 7, PageSize: 3, Style: itertest.PageToken})
+	defer server.Close()
+
+	var records []itertest.Record
+	for token := ""; ; {
+		var page struct {
+			Records       []itertest.Record
+			NextPageToken string `json:"next_page_token"`
+		}
+		get(t, server.URL+"?page_token="+url.QueryEscape(token), &page)
+		records = append(records, page.Records...)
+		if token = page.NextPageToken; token == "" {
+			break
+		}
+	}
+	checkIDs(t, records, 7)
+}
+
+func TestServerLink(t *testing.T) {
+	server := itertest.NewServer(itertest.ServerConfig{Records: 7, Style: itertest.Link})
+	defer server.Close()
+
+	iterator := iter.NewHTTP(iter.HTTPConfig[[]itertest.Record]{URL: server.URL + "/records?limit=3"})
+
+	var records []itertest.Record
+	err := iterator.Iterate(func(response []itertest.Record) error {
+		records = append(records, response...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkIDs(t, records, 7)
+}
+
+func TestServerFailEvery(t *testing.T) {
+	server := itertest.NewServer(itertest.ServerConfig{Records: 7, Style: itertest.Offset, FailEvery: 2})
+	defer server.Close()
+
+	var statuses []int
+	for i := 0; i < 4; i++ {
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		statuses = append(statuses, resp.StatusCode)
+	}
+
+	expected := []int{200, 500, 200, 500}
+	if fmt.Sprint(statuses) != fmt.Sprint(expected) {
+		t.Errorf("unexpected statuses: %v", statuses)
+	}
+}
@@ -0,0 +1,155 @@
+package iter
+
+import "errors"
+
+// Pair is a pair of values joined by key.
+type Pair[L, R any] struct {
+	Left  L
+	Right R
+}
+
+// HashJoin returns stream of pairs of left and right values with equal
+// keys. Values of right are loaded into memory on the first Get, values
+// of left are streamed, so right should be the smaller of the two.
+// Pairs are yielded in the order of left values.
+func HashJoin[L, R any, K comparable](
+	left *Stream[L],
+	right *Stream[R],
+	leftKey func(value L) K,
+	rightKey func(value R) K,
+) *Stream[Pair[L, R]] {
+	var (
+		index   map[K][]R
+		value   L
+		matches []R
+	)
+
+	return newStream(func() (Pair[L, R], error) {
+		if index == nil {
+			built := make(map[K][]R)
+			err := right.Iterate(func(value R) error {
+				key := rightKey(value)
+				built[key] = append(built[key], value)
+				return nil
+			})
+			if err != nil {
+				return Pair[L, R]{}, err
+			}
+			index = built
+		}
+
+		for len(matches) == 0 {
+			var err error
+			if value, err = left.Get(); err != nil {
+				return Pair[L, R]{}, err
+			}
+			matches = index[leftKey(value)]
+		}
+
+		pair := Pair[L, R]{Left: value, Right: matches[0]}
+		matches = matches[1:]
+		return pair, nil
+	}, func() {
+		left.Reset()
+		right.Reset()
+		index, matches = nil, nil
+	})
+}
+
+// MergeJoin returns stream of pairs of left and right values with equal
+// keys. Both streams must be sorted by key in ascending order according
+// to compare. Only values with the current key are kept in memory.
+func MergeJoin[L, R, K any](
+	left *Stream[L],
+	right *Stream[R],
+	leftKey func(value L) K,
+	rightKey func(value R) K,
+	compare func(a, b K) int,
+) *Stream[Pair[L, R]] {
+	var (
+		value     L
+		hasLeft   bool
+		next      R
+		hasNext   bool
+		rightDone bool
+		group     []R
+		groupKey  K
+		matching  bool
+		position  int
+	)
+
+	return newStream(func() (Pair[L, R], error) {
+		for {
+			if matching {
+				if position < len(group) {
+					position++
+					return Pair[L, R]{Left: value, Right: group[position-1]}, nil
+				}
+
+				var err error
+				if value, err = left.Get(); err != nil {
+					hasLeft = false
+					return Pair[L, R]{}, err
+				}
+				position = 0
+				if compare(leftKey(value), groupKey) == 0 {
+					continue
+				}
+				matching = false
+			}
+
+			if !hasLeft {
+				var err error
+				if value, err = left.Get(); err != nil {
+					return Pair[L, R]{}, err
+				}
+				hasLeft = true
+			}
+
+			if !hasNext {
+				if rightDone {
+					return Pair[L, R]{}, ErrStop
+				}
+				var err error
+				if next, err = right.Get(); err != nil {
+					return Pair[L, R]{}, err
+				}
+				hasNext = true
+			}
+
+			switch c := compare(leftKey(value), rightKey(next)); {
+			case c < 0:
+				hasLeft = false
+				continue
+			case c > 0:
+				hasNext = false
+				continue
+			}
+
+			// collect all right values with the same key
+			group, groupKey, hasNext = group[:0], rightKey(next), false
+			group = append(group, next)
+			for {
+				r, err := right.Get()
+				if errors.Is(err, ErrStop) {
+					rightDone = true
+					break
+				}
+				if err != nil {
+					return Pair[L, R]{}, err
+				}
+				if compare(rightKey(r), groupKey) != 0 {
+					next, hasNext = r, true
+					break
+				}
+				group = append(group, r)
+			}
+			matching, position = true, 0
+		}
+	}, func() {
+		left.Reset()
+		right.Reset()
+		hasLeft, hasNext, rightDone, matching = false, false, false, false
+		group, position = nil, 0
+	})
+}
@@ -0,0 +1,90 @@
+package iter_test
+
+import (
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+type user struct {
+	ID   int
+	Name string
+}
+
+type order struct {
+	UserID int
+	Item   string
+}
+
+func users() *iter.Stream[user] {
+	return iter.Items(iter.New(pagesConfig([][]user{
+		{{1, "ann"}, {2, "bob"}},
+		{{3, "cid"}, {5, "dan"}},
+	})))
+}
+
+func orders() *iter.Stream[order] {
+	return iter.Items(iter.New(pagesConfig([][]order{
+		{{1, "apple"}, {1, "pear"}},
+		{{3, "fig"}, {4, "kiwi"}, {5, "lime"}},
+		{{5, "plum"}},
+	})))
+}
+
+func joined(pairs []iter.Pair[order, user]) []string {
+	var names []string
+	for _, pair := range pairs {
+		names = append(names, pair.Right.Name+":"+pair.Left.Item)
+	}
+	return names
+}
+
+var expectedJoin = []string{"ann:apple", "ann:pear", "cid:fig", "dan:lime", "dan:plum"}
+
+func TestHashJoin(t *testing.T) {
+	stream := iter.HashJoin(orders(), users(), func(o order) int {
+		return o.UserID
+	}, func(u user) int {
+		return u.ID
+	})
+
+	if names := joined(collect(t, stream)); !reflect.DeepEqual(names, expectedJoin) {
+		t.Errorf("unexpected pairs: %v", names)
+	}
+	stream.Reset()
+	if names := joined(collect(t, stream)); !reflect.DeepEqual(names, expectedJoin) {
+		t.Errorf("unexpected pairs after reset: %v", names)
+	}
+}
+
+func TestMergeJoin(t *testing.T) {
+	compare := func(a, b int) int {
+		return a - b
+	}
+
+	stream := iter.MergeJoin(orders(), users(), func(o order) int {
+		return o.UserID
+	}, func(u user) int {
+		return u.ID
+	}, compare)
+
+	if names := joined(collect(t, stream)); !reflect.DeepEqual(names, expectedJoin) {
+		t.Errorf("unexpected pairs: %v", names)
+	}
+	stream.Reset()
+	if names := joined(collect(t, stream)); !reflect.DeepEqual(names, expectedJoin) {
+		t.Errorf("unexpected pairs after reset: %v", names)
+	}
+
+	t.Run("duplicates on both sides", func(t *testing.T) {
+		left := iter.Items(iter.New(pagesConfig([][]int{{1, 2, 2}, {3}})))
+		right := iter.Items(iter.New(pagesConfig([][]int{{2}, {2, 3, 3}})))
+		pairs := collect(t, iter.MergeJoin(left, right, func(v int) int { return v }, func(v int) int { return v }, compare))
+
+		expected := []iter.Pair[int, int]{{2, 2}, {2, 2}, {2, 2}, {2, 2}, {3, 3}, {3, 3}}
+		if !reflect.DeepEqual(pairs, expected) {
+			t.Errorf("unexpected pairs: %v", pairs)
+		}
+	})
+}
@@ -0,0 +1,23 @@
+package iter
+
+// NewPagedResults creates a cursor over LDAP searches using the simple
+// paged results control (RFC 2696). search should run the search with
+// the paging control carrying the cookie and return found entries along
+// with the cookie from the response control. The iteration ends when
+// the server returns an empty cookie.
+func NewPagedResults[Entry any](
+	search func(cookie []byte) (entries []Entry, next []byte, err error),
+) *Cursor[[]byte, Page[[]byte, Entry]] {
+	return New(Config[[]byte, Page[[]byte, Entry]]{
+		HasNext: func(result Page[[]byte, Entry]) ([]byte, bool) {
+			return result.Next, len(result.Next) > 0
+		},
+		FetchNext: func(input []byte) (Page[[]byte, Entry], error) {
+			entries, next, err := search(input)
+			return Page[[]byte, Entry]{Items: entries, Next: next}, err
+		},
+		GetFirstInput: func() []byte {
+			return nil
+		},
+	})
+}
@@ -0,0 +1,43 @@
+package iter_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestNewPagedResults(t *testing.T) {
+	entries := []string{"cn=a", "cn=b", "cn=c", "cn=d", "cn=e"}
+	var cookies []string
+
+	iterator := iter.NewPagedResults(func(cookie []byte) ([]string, []byte, error) {
+		cookies = append(cookies, string(cookie))
+		offset := 0
+		if len(cookie) > 0 {
+			offset, _ = strconv.Atoi(string(cookie))
+		}
+
+		end := offset + 2
+		if end >= len(entries) {
+			return entries[offset:], nil, nil
+		}
+		return entries[offset:end], []byte(strconv.Itoa(end)), nil
+	})
+
+	var results []string
+	err := iterator.Iterate(func(response iter.Page[[]byte, string]) error {
+		results = append(results, response.Items...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(results, entries) {
+		t.Errorf("unexpected entries: %+v", results)
+	}
+	if !reflect.DeepEqual(cookies, []string{"", "2", "4"}) {
+		t.Errorf("unexpected cookies: %+v", cookies)
+	}
+}
@@ -0,0 +1,48 @@
+package iter
+
+import "errors"
+
+var processedMark = []byte("1")
+
+// IterateOnce works like [Cursor.Iterate] but skips pages already
+// processed by a previous run. Every page processed without error is
+// recorded in ledger under key of the input used to fetch it, so
+// re-running a job after a crash does not pass the same page to
+// callback twice. Recorded pages are still fetched, because their
+// results drive HasNext.
+func (d *Cursor[Input, Result]) IterateOnce(
+	ledger CheckpointStore,
+	key func(input Input) string,
+	callback func(response Result) error,
+) error {
+	for d.Next() {
+		result, err := d.Get()
+		if err != nil {
+			if errors.Is(err, ErrStop) {
+				return nil
+			}
+			return err
+		}
+		page := key(d.last)
+
+		seen, err := ledger.Load(page)
+		if err != nil {
+			return err
+		}
+		if seen != nil {
+			continue
+		}
+
+		if err := callback(result); err != nil {
+			if errors.Is(err, ErrStop) {
+				return nil
+			}
+			return err
+		}
+		if err := ledger.Save(page, processedMark); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
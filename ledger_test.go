@@ -0,0 +1,50 @@
+package iter_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestIterateOnce(t *testing.T) {
+	crash := errors.New("crash")
+	ledger := iter.NewMemoryStore()
+	pages := [][]int{{1}, {2}, {3}, {4}}
+
+	run := func(failAt int) ([]int, error) {
+		var processed []int
+		cursor := iter.New(pagesConfig(pages))
+		err := cursor.IterateOnce(ledger, strconv.Itoa, func(page []int) error {
+			if page[0] == failAt {
+				return crash
+			}
+			processed = append(processed, page...)
+			return nil
+		})
+		return processed, err
+	}
+
+	processed, err := run(3)
+	if !errors.Is(err, crash) {
+		t.Fatalf("expected crash, got %v", err)
+	}
+	if !reflect.DeepEqual(processed, []int{1, 2}) {
+		t.Errorf("unexpected pages of crashed run: %v", processed)
+	}
+
+	processed, err = run(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(processed, []int{3, 4}) {
+		t.Errorf("unexpected pages of second run: %v", processed)
+	}
+
+	processed, err = run(0)
+	if err != nil || processed != nil {
+		t.Errorf("expected nothing to process, got %v, %v", processed, err)
+	}
+}
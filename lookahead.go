@@ -0,0 +1,31 @@
+package iter
+
+import "errors"
+
+// Lookahead returns up to n upcoming values without consuming them,
+// e.g. to pre-resolve foreign keys of items in upcoming pages before
+// processing the current one. Values are pulled from the underlying
+// cursor as needed and returned by Get later. Fewer values are
+// returned at the end of the stream. If pulling fails, values pulled
+// so far are returned along with the error.
+func (s *Stream[T]) Lookahead(n int) ([]T, error) {
+	for len(s.ahead) < n && !s.depleted && !s.done {
+		value, err := s.pull()
+		if errors.Is(err, ErrStop) {
+			s.depleted = true
+			break
+		}
+		if err != nil {
+			return s.peek(n), err
+		}
+		s.ahead = append(s.ahead, value)
+	}
+	return s.peek(n), nil
+}
+
+func (s *Stream[T]) peek(n int) []T {
+	if n > len(s.ahead) {
+		n = len(s.ahead)
+	}
+	return append([]T(nil), s.ahead[:n]...)
+}
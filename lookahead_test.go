@@ -0,0 +1,37 @@
+package iter_test
+
+import (
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestLookahead(t *testing.T) {
+	stream := iter.Pages(iter.New(pagesConfig([][]int{{1}, {2}, {3}})))
+
+	if _, err := stream.Get(); err != nil {
+		t.Fatal(err)
+	}
+	ahead, err := stream.Lookahead(5)
+	if err != nil || !reflect.DeepEqual(ahead, [][]int{{2}, {3}}) {
+		t.Fatalf("unexpected lookahead: %v, %v", ahead, err)
+	}
+	ahead, err = stream.Lookahead(1)
+	if err != nil || !reflect.DeepEqual(ahead, [][]int{{2}}) {
+		t.Fatalf("unexpected lookahead: %v, %v", ahead, err)
+	}
+
+	pages, err := stream.Collect()
+	if err != nil || !reflect.DeepEqual(pages, [][]int{{2}, {3}}) {
+		t.Errorf("unexpected pages: %v, %v", pages, err)
+	}
+	if stream.Next() {
+		t.Error("expected depleted stream")
+	}
+
+	stream.Reset()
+	if pages, err := stream.Collect(); err != nil || len(pages) != 3 {
+		t.Errorf("unexpected pages after reset: %v, %v", pages, err)
+	}
+}
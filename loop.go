@@ -0,0 +1,63 @@
+package iter
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInputLoop is returned by Get of cursors using [DetectLoops] when
+// an input repeats, e.g. when an API keeps returning the same next
+// page token.
+var ErrInputLoop = errors.New("input repeated")
+
+// DetectLoops returns middleware failing fetches of inputs already
+// fetched in the current iteration. Inputs are compared by key, so
+// inputs that are not comparable (structs with slices or maps) are
+// supported; nil key formats inputs with fmt. Keys of all fetched
+// inputs are kept until Reset. The input of the last page may be
+// fetched again by [Cursor.Retry].
+func DetectLoops[Input, Result any](key func(input Input) string) Middleware[Input, Result] {
+	if key == nil {
+		key = func(input Input) string {
+			return fmt.Sprintf("%#v", input)
+		}
+	}
+
+	return func(config Config[Input, Result]) Config[Input, Result] {
+		var (
+			seen    = make(map[string]struct{})
+			last    string
+			refetch bool
+		)
+
+		getFirstInput := config.GetFirstInput
+		config.GetFirstInput = func() Input {
+			seen, refetch = make(map[string]struct{}), false
+			return getFirstInput()
+		}
+
+		config.onRefetch(func() {
+			refetch = true
+		})
+
+		config.wrapFetch(func(fetchNext func(input Input) (Result, error)) func(input Input) (Result, error) {
+			return func(input Input) (Result, error) {
+				k := key(input)
+				again := refetch && k == last
+				refetch = false
+				if _, ok := seen[k]; ok && !again {
+					var zero Result
+					return zero, fmt.Errorf("%w: %s", ErrInputLoop, k)
+				}
+
+				result, err := fetchNext(input)
+				if err == nil {
+					seen[k], last = struct{}{}, k
+				}
+				return result, err
+			}
+		})
+
+		return config
+	}
+}
@@ -0,0 +1,55 @@
+package iter_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestDetectLoops(t *testing.T) {
+	type input struct {
+		Filters []string
+		Token   string
+	}
+	// the API hands out the first token again after the second page
+	tokens := map[string]string{"": "a", "a": "b", "b": "a"}
+
+	config := iter.Config[input, string]{
+		HasNext: func(result string) (input, bool) {
+			return input{Filters: []string{"x"}, Token: result}, true
+		},
+		FetchNext: func(in input) (string, error) {
+			return tokens[in.Token], nil
+		},
+		GetFirstInput: func() input {
+			return input{Filters: []string{"x"}}
+		},
+	}
+	key := func(in input) string {
+		return strings.Join(in.Filters, ",") + "/" + in.Token
+	}
+
+	cursor := iter.New(iter.Use(config, iter.DetectLoops[input, string](key)))
+	pages, err := cursor.Collect()
+	if !errors.Is(err, iter.ErrInputLoop) {
+		t.Fatalf("expected loop, got %v", err)
+	}
+	if len(pages) != 3 {
+		t.Errorf("unexpected pages before loop: %v", pages)
+	}
+
+	cursor.Reset()
+	if _, err := cursor.CollectN(3); err != nil {
+		t.Errorf("unexpected error after reset: %v", err)
+	}
+}
+
+func TestDetectLoopsRetry(t *testing.T) {
+	cursor := iter.New(iter.Use(pagesConfig([][]int{{1}, {2}, {3}}), iter.DetectLoops[int, []int](nil)))
+	pages, err := collectRetrying(cursor)
+	if err != nil || len(pages) != 3 {
+		t.Errorf("expected retried pages, got %v, %v", pages, err)
+	}
+}
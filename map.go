@@ -0,0 +1,75 @@
+package iter
+
+// Map returns stream of values of the stream transformed by f. Up to
+// workers calls of f run concurrently, but values are yielded in the
+// original order. An error returned by f is returned by Get at the
+// position of the value.
+func Map[T, U any](stream *Stream[T], workers int, f func(value T) (U, error)) *Stream[U] {
+	if workers < 1 {
+		workers = 1
+	}
+	return mapStream(stream, func() int { return workers }, f)
+}
+
+// mapStream is [Map] with concurrency limited by the result of workers
+// checked before every call.
+func mapStream[T, U any](stream *Stream[T], workers func() int, f func(value T) (U, error)) *Stream[U] {
+	var (
+		pending   []chan Result[U]
+		sourceErr error
+	)
+
+	return newStream(func() (U, error) {
+		for sourceErr == nil && len(pending) < workers() {
+			value, err := stream.Get()
+			if err != nil {
+				sourceErr = err
+				break
+			}
+
+			result := make(chan Result[U], 1)
+			go func() {
+				mapped, err := f(value)
+				result <- Result[U]{Value: mapped, Err: err}
+			}()
+			pending = append(pending, result)
+		}
+
+		if len(pending) == 0 {
+			var zero U
+			err := sourceErr
+			sourceErr = nil
+			return zero, err
+		}
+
+		result := <-pending[0]
+		pending = pending[1:]
+		return result.Value, result.Err
+	}, func() {
+		for _, result := range pending {
+			<-result
+		}
+		pending, sourceErr = nil, nil
+		stream.Reset()
+	})
+}
+
+// MapPages returns stream of Results fetched by the cursor transformed
+// by f with up to workers concurrent calls, see [Map].
+func MapPages[Input, Result, U any](
+	cursor *Cursor[Input, Result],
+	workers int,
+	f func(result Result) (U, error),
+) *Stream[U] {
+	return Map(Pages(cursor), workers, f)
+}
+
+// MapItems returns stream of items of pages fetched by the cursor
+// transformed by f with up to workers concurrent calls, see [Map].
+func MapItems[Input, T, U any](
+	cursor *Cursor[Input, []T],
+	workers int,
+	f func(item T) (U, error),
+) *Stream[U] {
+	return Map(Items(cursor), workers, f)
+}
@@ -0,0 +1,78 @@
+package iter_test
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.teddydd.me/iter"
+)
+
+func TestMapItems(t *testing.T) {
+	var running, peak int32
+
+	square := func(n int) (int, error) {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if current <= p || atomic.CompareAndSwapInt32(&peak, p, current) {
+				break
+			}
+		}
+		// later items finish first
+		time.Sleep(time.Duration(10-n) * time.Millisecond)
+		return n * n, nil
+	}
+
+	stream := iter.MapItems(iter.New(pagesConfig([][]int{{1, 2, 3}, {4, 5}, {6, 7, 8, 9}})), 4, square)
+	values := collect(t, stream)
+
+	if !reflect.DeepEqual(values, []int{1, 4, 9, 16, 25, 36, 49, 64, 81}) {
+		t.Errorf("unexpected values: %v", values)
+	}
+	if peak < 2 || peak > 4 {
+		t.Errorf("expected 2 to 4 concurrent calls, got %d", peak)
+	}
+
+	stream.Reset()
+	if values := collect(t, stream); len(values) != 9 {
+		t.Errorf("unexpected values after reset: %v", values)
+	}
+}
+
+func TestMapPagesError(t *testing.T) {
+	failure := errors.New("failure")
+
+	stream := iter.MapPages(iter.New(pagesConfig([][]int{{1}, {2, 3}, {4}})), 2, func(page []int) (int, error) {
+		if len(page) > 1 {
+			return 0, failure
+		}
+		return page[0], nil
+	})
+
+	values, err := stream.Collect()
+	if !errors.Is(err, failure) {
+		t.Errorf("expected failure, got %v", err)
+	}
+	if !reflect.DeepEqual(values, []int{1}) {
+		t.Errorf("unexpected values: %v", values)
+	}
+}
+
+func TestMapAbandoned(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	stream := iter.Map(numbers(20, 5), 4, func(n int) (int, error) {
+		time.Sleep(time.Millisecond)
+		return n, nil
+	})
+	if _, err := stream.Get(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	checkGoroutines(t, before)
+}
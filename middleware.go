@@ -0,0 +1,91 @@
+package iter
+
+import "context"
+
+// Middleware decorates cursor configuration, e.g. to add retries,
+// metrics or logging around FetchNext. Middlewares that keep state
+// should reset it by wrapping GetFirstInput, and middlewares accounting
+// fetched pages should not count the last page twice when it is fetched
+// again, see Refetch of [Config]. Middlewares decorating fetches should
+// decorate all fetch functions of the config, see [WrapFetch] and
+// [WrapFetchContext].
+type Middleware[Input, Result any] func(config Config[Input, Result]) Config[Input, Result]
+
+// Use applies middlewares to config. The first middleware is the
+// outermost one, so it sees calls before the others.
+func Use[Input, Result any](
+	config Config[Input, Result],
+	middlewares ...Middleware[Input, Result],
+) Config[Input, Result] {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		config = middlewares[i](config)
+	}
+	return config
+}
+
+// WrapFetch creates a middleware decorating FetchNext, FetchFirst and
+// their context-aware variants. For context-aware functions wrap is
+// called on every fetch, so it should keep its state in the middleware
+// rather than in its own body.
+func WrapFetch[Input, Result any](
+	wrap func(fetchNext func(input Input) (Result, error)) func(input Input) (Result, error),
+) Middleware[Input, Result] {
+	return func(config Config[Input, Result]) Config[Input, Result] {
+		config.wrapFetch(wrap)
+		return config
+	}
+}
+
+// WrapFetchContext creates a middleware decorating fetches along with
+// their context, e.g. to cancel them. Functions without context are
+// adapted to FetchNextContext and FetchFirstContext, which take
+// precedence over them.
+func WrapFetchContext[Input, Result any](
+	wrap func(fetchNext func(ctx context.Context, input Input) (Result, error)) func(ctx context.Context, input Input) (Result, error),
+) Middleware[Input, Result] {
+	return func(config Config[Input, Result]) Config[Input, Result] {
+		config.wrapFetchContext(wrap, wrap)
+		return config
+	}
+}
+
+// wrapFetch decorates all fetch functions that are set with wrap.
+func (c *Config[Input, Result]) wrapFetch(
+	wrap func(fetch func(input Input) (Result, error)) func(input Input) (Result, error),
+) {
+	if c.FetchNext != nil {
+		c.FetchNext = wrap(c.FetchNext)
+	}
+	if c.FetchFirst != nil {
+		c.FetchFirst = wrap(c.FetchFirst)
+	}
+
+	withoutContext := func(
+		fetch func(ctx context.Context, input Input) (Result, error),
+	) func(ctx context.Context, input Input) (Result, error) {
+		return func(ctx context.Context, input Input) (Result, error) {
+			return wrap(func(input Input) (Result, error) {
+				return fetch(ctx, input)
+			})(input)
+		}
+	}
+	if c.FetchNextContext != nil {
+		c.FetchNextContext = withoutContext(c.FetchNextContext)
+	}
+	if c.FetchFirstContext != nil {
+		c.FetchFirstContext = withoutContext(c.FetchFirstContext)
+	}
+}
+
+// wrapFetchContext decorates context-aware fetches of the next and of
+// the first page, adapting functions without context.
+func (c *Config[Input, Result]) wrapFetchContext(
+	next, first func(fetch func(ctx context.Context, input Input) (Result, error)) func(ctx context.Context, input Input) (Result, error),
+) {
+	if fetch := c.fetchNextContext(); fetch != nil {
+		c.FetchNextContext = next(fetch)
+	}
+	if fetch := c.fetchFirstContext(); fetch != nil {
+		c.FetchFirstContext = first(fetch)
+	}
+}
@@ -0,0 +1,114 @@
+package iter_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestUse(t *testing.T) {
+	var calls []string
+
+	trace := func(name string) iter.Middleware[int, []int] {
+		return iter.WrapFetch(func(fetchNext func(int) ([]int, error)) func(int) ([]int, error) {
+			return func(input int) ([]int, error) {
+				calls = append(calls, fmt.Sprintf("%s:%d", name, input))
+				return fetchNext(input)
+			}
+		})
+	}
+
+	config := iter.Use(pagesConfig([][]int{{1}, {2}}), trace("outer"), trace("inner"))
+	iterator := iter.New(config)
+
+	if err := iterator.Iterate(func([]int) error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"outer:0", "inner:0", "outer:1", "inner:1"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+}
+
+func TestWrapFetchRetry(t *testing.T) {
+	failures := 2
+	flaky := iter.WrapFetch(func(fetchNext func(int) ([]int, error)) func(int) ([]int, error) {
+		return func(input int) ([]int, error) {
+			if failures > 0 {
+				failures--
+				return nil, errors.New("temporary")
+			}
+			return fetchNext(input)
+		}
+	})
+	retry := iter.WrapFetch(func(fetchNext func(int) ([]int, error)) func(int) ([]int, error) {
+		return func(input int) (result []int, err error) {
+			for attempt := 0; attempt < 3; attempt++ {
+				if result, err = fetchNext(input); err == nil {
+					break
+				}
+			}
+			return result, err
+		}
+	})
+
+	iterator := iter.New(iter.Use(pagesConfig([][]int{{1, 2}}), retry, flaky))
+	result, err := iterator.Get()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result, []int{1, 2}) {
+		t.Errorf("unexpected result: %v", result)
+	}
+}
+
+func TestWrapFetchContext(t *testing.T) {
+	type key struct{}
+	var seen []any
+	withContext := iter.WrapFetchContext(func(fetch func(context.Context, int) ([]int, error)) func(context.Context, int) ([]int, error) {
+		return func(ctx context.Context, input int) ([]int, error) {
+			return fetch(context.WithValue(ctx, key{}, input), input)
+		}
+	})
+	// plain middlewares see fetches of context-aware configs
+	fetches := 0
+	count := iter.WrapFetch(func(fetch func(int) ([]int, error)) func(int) ([]int, error) {
+		return func(input int) ([]int, error) {
+			fetches++
+			return fetch(input)
+		}
+	})
+
+	config := pagesConfig([][]int{{1}, {2}})
+	fetchNext := config.FetchNext
+	config.FetchNext = nil
+	config.FetchNextContext = func(ctx context.Context, input int) ([]int, error) {
+		seen = append(seen, ctx.Value(key{}))
+		return fetchNext(input)
+	}
+
+	if _, err := iter.New(iter.Use(config, count, withContext)).Collect(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(seen, []any{0, 1}) || fetches != 2 {
+		t.Errorf("unexpected fetches: %v, %d", seen, fetches)
+	}
+
+	// functions without context are adapted
+	seen = nil
+	plain := pagesConfig([][]int{{1}})
+	cursor := iter.New(iter.Use(plain, withContext, iter.WrapFetchContext(func(fetch func(context.Context, int) ([]int, error)) func(context.Context, int) ([]int, error) {
+		return func(ctx context.Context, input int) ([]int, error) {
+			seen = append(seen, ctx.Value(key{}))
+			return fetch(ctx, input)
+		}
+	})))
+	if _, err := cursor.Collect(); err != nil || !reflect.DeepEqual(seen, []any{0}) {
+		t.Errorf("unexpected fetches: %v, %v", seen, err)
+	}
+}
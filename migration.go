@@ -0,0 +1,62 @@
+package iter
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrCheckpointVersion is returned when a checkpoint can not be upgraded
+// to the current version of its Input type.
+var ErrCheckpointVersion = errors.New("unsupported checkpoint version")
+
+// Migration upgrades input encoded in a checkpoint by one version. Input
+// is encoded with the [Codec] registered for its type, or as JSON if
+// there is none.
+type Migration func(input []byte) ([]byte, error)
+
+type schema struct {
+	version    int
+	migrations []Migration
+}
+
+var versions sync.Map
+
+// RegisterVersion sets the current checkpoint version of given Input
+// type, so checkpoints written by previous versions of a program can
+// still be resumed after the Input type evolves. migrations[v] upgrades
+// input from version v to v+1. Checkpoints written before a version was
+// registered have version 0.
+func RegisterVersion[Input any](version int, migrations ...Migration) {
+	versions.Store(typeOf[Input](), schema{
+		version:    version,
+		migrations: migrations,
+	})
+}
+
+func versionFor[Input any]() schema {
+	version, _ := versions.Load(typeOf[Input]())
+	current, _ := version.(schema)
+	return current
+}
+
+func migrate[Input any](version int, input []byte) ([]byte, error) {
+	current := versionFor[Input]()
+	if version > current.version {
+		return nil, fmt.Errorf("%w: %d is newer than %d", ErrCheckpointVersion, version, current.version)
+	}
+
+	for ; version < current.version; version++ {
+		if version < 0 || version >= len(current.migrations) || current.migrations[version] == nil {
+			return nil, fmt.Errorf("%w: no migration from %d", ErrCheckpointVersion, version)
+		}
+
+		var err error
+		input, err = current.migrations[version](input)
+		if err != nil {
+			return nil, fmt.Errorf("migrating checkpoint from version %d: %w", version, err)
+		}
+	}
+
+	return input, nil
+}
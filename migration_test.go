@@ -0,0 +1,56 @@
+package iter_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+type pageInput struct {
+	Page int `json:"page"`
+	Size int `json:"size"`
+}
+
+func init() {
+	iter.RegisterVersion[pageInput](1, func(input []byte) ([]byte, error) {
+		// version 0 stored only the page number
+		var page int
+		if err := json.Unmarshal(input, &page); err != nil {
+			return nil, err
+		}
+		return json.Marshal(pageInput{Page: page, Size: 50})
+	})
+}
+
+func TestCheckpointMigration(t *testing.T) {
+	var checkpoint iter.Checkpoint[pageInput]
+	if err := json.Unmarshal([]byte(`{"input":3,"done":false}`), &checkpoint); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if checkpoint.Input != (pageInput{Page: 3, Size: 50}) {
+		t.Errorf("expected migrated input, got %+v", checkpoint.Input)
+	}
+
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"version":1,"input":{"page":3,"size":50},"done":false}` {
+		t.Errorf("unexpected checkpoint: %s", data)
+	}
+
+	var current iter.Checkpoint[pageInput]
+	if err := json.Unmarshal(data, &current); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current != checkpoint {
+		t.Errorf("expected current checkpoint to round-trip, got %+v", current)
+	}
+
+	err = json.Unmarshal([]byte(`{"version":2,"input":{}}`), &current)
+	if !errors.Is(err, iter.ErrCheckpointVersion) {
+		t.Errorf("expected ErrCheckpointVersion for newer checkpoint, got %+v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package iter
+
+import "context"
+
+// NestedInput is the combined position of a cursor created by
+// [NewNested]. Persist it to resume both levels of the iteration.
+type NestedInput[OuterInput, InnerInput any] struct {
+	// Outer is the input of the outer page holding the current parent.
+	Outer OuterInput
+	// Index is the index of the current parent within the outer page.
+	Index int
+	// Inner is the input of the next inner page of the current parent.
+	// It is nil if iteration of the parent has not started yet.
+	Inner *InnerInput
+}
+
+// NestedPage is an inner Result along with the parent it belongs to.
+type NestedPage[Parent, Result any] struct {
+	Parent Parent
+	Result Result
+}
+
+// NestedConfig configures two-level pagination, for example accounts
+// and transactions of each account.
+type NestedConfig[OuterInput, Parent, InnerInput, Result any] struct {
+	// Outer paginates over parents.
+	Outer Config[OuterInput, []Parent]
+	// HasNext checks if response indicates there is more Results of
+	// the parent to fetch.
+	HasNext func(result Result) (InnerInput, bool)
+	// FetchNext should fetch next Result of the parent.
+	FetchNext func(parent Parent, input InnerInput) (Result, error)
+	// GetFirstInput must return initial input for the parent.
+	GetFirstInput func(parent Parent) InnerInput
+	// Resume is optional. It returns previously persisted position to
+	// start from and true, or false to start from the beginning.
+	Resume func() (NestedInput[OuterInput, InnerInput], bool)
+}
+
+// NewNested creates a cursor over two-level pagination. Unlike
+// flattening separate cursors, its input tracks both levels, so the
+// iteration can be resumed from [Cursor.Input]. Get returns ErrStop
+// if the remaining outer pages have no parents.
+func NewNested[OuterInput, Parent, InnerInput, Result any](
+	config NestedConfig[OuterInput, Parent, InnerInput, Result],
+) *Cursor[NestedInput[OuterInput, InnerInput], NestedPage[Parent, Result]] {
+	type (
+		input = NestedInput[OuterInput, InnerInput]
+		page  = NestedPage[Parent, Result]
+	)
+
+	var (
+		loaded       bool
+		position     input
+		parents      []Parent
+		outerNext    OuterInput
+		outerHasNext bool
+	)
+
+	outerFetch := config.Outer.fetchNextContext()
+
+	return New(Config[input, page]{
+		HasNext: func(result page) (input, bool) {
+			if next, ok := config.HasNext(result.Result); ok {
+				return input{
+					Outer: position.Outer,
+					Index: position.Index,
+					Inner: &next,
+				}, true
+			}
+
+			if position.Index+1 < len(parents) {
+				return input{
+					Outer: position.Outer,
+					Index: position.Index + 1,
+				}, true
+			}
+
+			return input{Outer: outerNext}, outerHasNext
+		},
+		FetchNextContext: func(ctx context.Context, next input) (page, error) {
+			for {
+				if !loaded || (next.Inner == nil && next.Index == 0) {
+					var err error
+					parents, err = outerFetch(ctx, next.Outer)
+					if err != nil {
+						loaded = false
+						return page{}, err
+					}
+					outerNext, outerHasNext = config.Outer.HasNext(parents)
+					loaded = true
+				}
+
+				if next.Index < len(parents) {
+					break
+				}
+				if !outerHasNext {
+					return page{}, ErrStop
+				}
+				next = input{Outer: outerNext}
+			}
+
+			parent := parents[next.Index]
+			if next.Inner == nil {
+				first := config.GetFirstInput(parent)
+				next.Inner = &first
+			}
+			position = next
+
+			result, err := config.FetchNext(parent, *next.Inner)
+			return page{Parent: parent, Result: result}, err
+		},
+		GetFirstInput: func() input {
+			loaded = false
+			if config.Resume != nil {
+				if position, ok := config.Resume(); ok {
+					return position
+				}
+			}
+			return input{Outer: config.Outer.GetFirstInput()}
+		},
+	})
+}
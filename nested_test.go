@@ -0,0 +1,126 @@
+package iter_test
+
+import (
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+type transactions struct {
+	IDs  []int
+	Next int
+	More bool
+}
+
+func nestedConfig() iter.NestedConfig[int, string, int, transactions] {
+	accounts := map[string][]int{
+		"a": {1, 2, 3},
+		"b": {10},
+		"c": {20, 21},
+	}
+
+	return iter.NestedConfig[int, string, int, transactions]{
+		Outer: pagesConfig([][]string{{"a", "b"}, {}, {"c"}}),
+		HasNext: func(result transactions) (int, bool) {
+			return result.Next, result.More
+		},
+		FetchNext: func(account string, offset int) (transactions, error) {
+			ids := accounts[account][offset:]
+			if len(ids) > 2 {
+				ids = ids[:2]
+			}
+			next := offset + len(ids)
+			return transactions{IDs: ids, Next: next, More: next < len(accounts[account])}, nil
+		},
+		GetFirstInput: func(account string) int {
+			return 0
+		},
+	}
+}
+
+type nestedResult struct {
+	account string
+	ids     []int
+}
+
+func collectNested(t *testing.T, iterator *iter.Cursor[iter.NestedInput[int, int], iter.NestedPage[string, transactions]], limit int) []nestedResult {
+	t.Helper()
+
+	var results []nestedResult
+	err := iterator.Iterate(func(response iter.NestedPage[string, transactions]) error {
+		results = append(results, nestedResult{account: response.Parent, ids: response.Result.IDs})
+		if len(results) == limit {
+			return iter.ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	return results
+}
+
+func TestNested(t *testing.T) {
+	expected := []nestedResult{
+		{account: "a", ids: []int{1, 2}},
+		{account: "a", ids: []int{3}},
+		{account: "b", ids: []int{10}},
+		{account: "c", ids: []int{20, 21}},
+	}
+
+	iterator := iter.NewNested(nestedConfig())
+	results := collectNested(t, iterator, -1)
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("unexpected results: got %+v, want %+v", results, expected)
+	}
+
+	iterator.Reset()
+	results = collectNested(t, iterator, -1)
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("unexpected results after reset: got %+v, want %+v", results, expected)
+	}
+
+	for processed := 1; processed < len(expected); processed++ {
+		iterator := iter.NewNested(nestedConfig())
+		collectNested(t, iterator, processed)
+		position := iterator.Input()
+
+		config := nestedConfig()
+		config.Resume = func() (iter.NestedInput[int, int], bool) {
+			return position, true
+		}
+		results := collectNested(t, iter.NewNested(config), -1)
+		if !reflect.DeepEqual(results, expected[processed:]) {
+			t.Errorf("unexpected results after resuming from %+v: got %+v, want %+v", position, results, expected[processed:])
+		}
+	}
+}
+
+func TestNestedEmptyOuterPages(t *testing.T) {
+	tests := map[string][][]string{
+		"trailing empty page": {{"a"}, {}},
+		"only empty page":     {{}},
+	}
+
+	for name, pages := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := nestedConfig()
+			config.Outer = pagesConfig(pages)
+
+			var expected []nestedResult
+			if len(pages[0]) > 0 {
+				expected = []nestedResult{
+					{account: "a", ids: []int{1, 2}},
+					{account: "a", ids: []int{3}},
+				}
+			}
+
+			results := collectNested(t, iter.NewNested(config), -1)
+			if !reflect.DeepEqual(results, expected) {
+				t.Errorf("unexpected results: got %+v, want %+v", results, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package iter
+
+// Page is a page of items returned by token paginated APIs along with
+// the token of the next page.
+type Page[Token, Item any] struct {
+	Items []Item
+	Next  Token
+}
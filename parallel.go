@@ -0,0 +1,105 @@
+package iter
+
+import "sync"
+
+// ProcessByKey calls process for every value of the stream using up to
+// workers goroutines. Values with the same key are processed one at a
+// time in stream order, while values with different keys are processed
+// concurrently, e.g. to apply ordered updates per entity. The first
+// error returned by process stops the processing and is returned.
+func ProcessByKey[T any, K comparable](
+	stream *Stream[T],
+	workers int,
+	key func(value T) K,
+	process func(value T) error,
+) error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	type job struct {
+		key   K
+		value T
+	}
+	type assignment struct {
+		worker  int
+		pending int
+	}
+
+	var (
+		mu     sync.Mutex
+		active = make(map[K]*assignment)
+		wg     sync.WaitGroup
+		once   sync.Once
+		failed error
+		stop   = make(chan struct{})
+	)
+
+	fail := func(err error) {
+		once.Do(func() {
+			failed = err
+			close(stop)
+		})
+	}
+
+	queues := make([]chan job, workers)
+	for i := range queues {
+		queues[i] = make(chan job)
+
+		wg.Add(1)
+		go func(queue chan job) {
+			defer wg.Done()
+			for job := range queue {
+				select {
+				case <-stop:
+				default:
+					if err := process(job.value); err != nil {
+						fail(err)
+					}
+				}
+
+				mu.Lock()
+				a := active[job.key]
+				a.pending--
+				if a.pending == 0 {
+					delete(active, job.key)
+				}
+				mu.Unlock()
+			}
+		}(queues[i])
+	}
+
+	// keys with pending values stay on their worker, others are
+	// assigned round-robin
+	var next int
+	err := stream.Iterate(func(value T) error {
+		k := key(value)
+
+		mu.Lock()
+		a, ok := active[k]
+		if !ok {
+			a = &assignment{worker: next}
+			active[k] = a
+			next = (next + 1) % workers
+		}
+		a.pending++
+		mu.Unlock()
+
+		select {
+		case queues[a.worker] <- job{key: k, value: value}:
+			return nil
+		case <-stop:
+			return ErrStop
+		}
+	})
+
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+
+	if failed != nil {
+		return failed
+	}
+	return err
+}
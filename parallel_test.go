@@ -0,0 +1,108 @@
+package iter_test
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.teddydd.me/iter"
+)
+
+type update struct {
+	Entity  int
+	Version int
+}
+
+func updates(entities, versions int) *iter.Stream[update] {
+	var pages [][]update
+	for version := 1; version <= versions; version++ {
+		var page []update
+		for entity := 0; entity < entities; entity++ {
+			page = append(page, update{Entity: entity, Version: version})
+		}
+		pages = append(pages, page)
+	}
+	return iter.Items(iter.New(pagesConfig(pages)))
+}
+
+func TestProcessByKey(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		applied = map[int][]int{}
+		running int32
+		maxRun  int32
+	)
+
+	err := iter.ProcessByKey(updates(8, 5), 4, func(u update) int {
+		return u.Entity
+	}, func(u update) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			peak := atomic.LoadInt32(&maxRun)
+			if n <= peak || atomic.CompareAndSwapInt32(&maxRun, peak, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		applied[u.Entity] = append(applied[u.Entity], u.Version)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for entity := 0; entity < 8; entity++ {
+		if !reflect.DeepEqual(applied[entity], []int{1, 2, 3, 4, 5}) {
+			t.Errorf("updates of entity %d applied out of order: %v", entity, applied[entity])
+		}
+	}
+	if maxRun < 2 {
+		t.Errorf("expected concurrent processing, max running %d", maxRun)
+	}
+}
+
+func TestProcessByKeyError(t *testing.T) {
+	failure := errors.New("failure")
+	var processed int32
+
+	err := iter.ProcessByKey(updates(4, 10), 2, func(u update) int {
+		return u.Entity
+	}, func(u update) error {
+		atomic.AddInt32(&processed, 1)
+		if u.Entity == 1 && u.Version == 2 {
+			return failure
+		}
+		return nil
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("expected failure, got %v", err)
+	}
+	if n := atomic.LoadInt32(&processed); n == 40 {
+		t.Errorf("expected processing to stop early")
+	}
+}
+
+func TestProcessByKeyLeak(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	for _, failure := range []error{nil, errors.New("failure")} {
+		iter.ProcessByKey(updates(4, 10), 3, func(u update) int {
+			return u.Entity
+		}, func(u update) error {
+			if u.Version == 5 {
+				return failure
+			}
+			return nil
+		})
+	}
+
+	checkGoroutines(t, before)
+}
@@ -0,0 +1,47 @@
+package iter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrNoSink is returned by [Partition] for values of a class without a
+// sink.
+var ErrNoSink = errors.New("no sink for class")
+
+// Sink consumes values, e.g. writes them to a file.
+type Sink[T any] interface {
+	Write(value T) error
+}
+
+// SinkFunc is a function used as a [Sink].
+type SinkFunc[T any] func(value T) error
+
+// Write calls f(value).
+func (f SinkFunc[T]) Write(value T) error {
+	return f(value)
+}
+
+// JSONLines returns sink writing values to w as JSON lines.
+func JSONLines[T any](w io.Writer) Sink[T] {
+	encoder := json.NewEncoder(w)
+	return SinkFunc[T](func(value T) error {
+		return encoder.Encode(value)
+	})
+}
+
+// Partition routes values of the stream to sinks by class in a single
+// pass, e.g. to split an export by region. Values of a class without
+// sink fail with [ErrNoSink]. Sinks are not closed or flushed.
+func Partition[T any](stream *Stream[T], classify func(value T) string, sinks map[string]Sink[T]) error {
+	return stream.Iterate(func(value T) error {
+		class := classify(value)
+		sink, ok := sinks[class]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrNoSink, class)
+		}
+		return sink.Write(value)
+	})
+}
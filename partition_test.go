@@ -0,0 +1,62 @@
+package iter_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+type customer struct {
+	ID     int    `json:"id"`
+	Region string `json:"region"`
+}
+
+func customers() *iter.Stream[customer] {
+	return iter.Items(iter.New(pagesConfig([][]customer{
+		{{1, "eu"}, {2, "us"}},
+		{{3, "eu"}, {4, "apac"}},
+	})))
+}
+
+func TestPartition(t *testing.T) {
+	var (
+		eu   bytes.Buffer
+		rest []int
+	)
+	others := iter.SinkFunc[customer](func(c customer) error {
+		rest = append(rest, c.ID)
+		return nil
+	})
+
+	err := iter.Partition(customers(), func(c customer) string {
+		return c.Region
+	}, map[string]iter.Sink[customer]{
+		"eu":   iter.JSONLines[customer](&eu),
+		"us":   others,
+		"apac": others,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if eu.String() != "{\"id\":1,\"region\":\"eu\"}\n{\"id\":3,\"region\":\"eu\"}\n" {
+		t.Errorf("unexpected eu output: %q", eu.String())
+	}
+	if !reflect.DeepEqual(rest, []int{2, 4}) {
+		t.Errorf("unexpected other customers: %v", rest)
+	}
+}
+
+func TestPartitionNoSink(t *testing.T) {
+	err := iter.Partition(customers(), func(c customer) string {
+		return c.Region
+	}, map[string]iter.Sink[customer]{
+		"eu": iter.SinkFunc[customer](func(customer) error { return nil }),
+	})
+	if !errors.Is(err, iter.ErrNoSink) {
+		t.Errorf("expected ErrNoSink, got %v", err)
+	}
+}
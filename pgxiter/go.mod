@@ -0,0 +1,15 @@
+module go.teddydd.me/iter/pgxiter
+
+go 1.20
+
+require (
+	github.com/jackc/pgx/v5 v5.5.5
+	go.teddydd.me/iter v0.0.0-20261016144633-3d0865cf0207
+)
+
+require (
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20221227161230-091c0ba34f0a // indirect
+	golang.org/x/crypto v0.17.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
@@ -0,0 +1,106 @@
+// Package pgxiter provides keyset paginated cursors over PostgreSQL
+// queries executed with pgx. It lives in a separate module, so users of
+// iter that do not need it don't depend on pgx.
+package pgxiter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.teddydd.me/iter"
+)
+
+// Querier runs queries. It is implemented by *pgx.Conn, pgx.Tx and
+// *pgxpool.Pool.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// Keyset describes keyset pagination over a query.
+type Keyset struct {
+	// Query is the base query without ORDER BY and LIMIT clauses. It
+	// must select all key columns.
+	Query string
+	// Args are arguments of the base query.
+	Args []any
+	// Keys are the names of key columns, most significant first. Keys
+	// must uniquely identify a row, e.g. {"created_at", "id"}.
+	Keys []string
+	// Descending iterates from the highest key to the lowest.
+	Descending bool
+	// Limit is the size of a page.
+	Limit int
+}
+
+// SQL returns query fetching the page after the given key values. The
+// first page is fetched when after is nil.
+func (k Keyset) SQL(after []any) (string, []any) {
+	var sql strings.Builder
+	args := append([]any{}, k.Args...)
+
+	fmt.Fprintf(&sql, "SELECT * FROM (%s) AS keyset", k.Query)
+
+	keys := strings.Join(k.Keys, ", ")
+	if after != nil {
+		placeholders := make([]string, len(after))
+		for i, value := range after {
+			args = append(args, value)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+
+		op := ">"
+		if k.Descending {
+			op = "<"
+		}
+		fmt.Fprintf(&sql, " WHERE (%s) %s (%s)", keys, op, strings.Join(placeholders, ", "))
+	}
+
+	order := make([]string, len(k.Keys))
+	for i, key := range k.Keys {
+		order[i] = key
+		if k.Descending {
+			order[i] += " DESC"
+		}
+	}
+	args = append(args, k.Limit)
+	fmt.Fprintf(&sql, " ORDER BY %s LIMIT $%d", strings.Join(order, ", "), len(args))
+
+	return sql.String(), args
+}
+
+// Config configures a keyset paginated cursor.
+type Config[T any] struct {
+	Keyset Keyset
+	// Scan converts a row into T, e.g. pgx.RowToStructByName[T].
+	Scan pgx.RowToFunc[T]
+	// Key returns values of key columns of the row, in the order of
+	// Keyset.Keys.
+	Key func(row T) []any
+}
+
+// New creates a cursor paging through rows of the query. Its input holds
+// key values of the last fetched row, nil before the first page, and can
+// be persisted to resume the iteration.
+func New[T any](ctx context.Context, db Querier, config Config[T]) *iter.Cursor[[]any, []T] {
+	return iter.New(iter.Config[[]any, []T]{
+		HasNext: func(result []T) ([]any, bool) {
+			if len(result) < config.Keyset.Limit || len(result) == 0 {
+				return nil, false
+			}
+			return config.Key(result[len(result)-1]), true
+		},
+		FetchNext: func(input []any) ([]T, error) {
+			sql, args := config.Keyset.SQL(input)
+			rows, err := db.Query(ctx, sql, args...)
+			if err != nil {
+				return nil, err
+			}
+			return pgx.CollectRows(rows, config.Scan)
+		},
+		GetFirstInput: func() []any {
+			return nil
+		},
+	})
+}
@@ -0,0 +1,146 @@
+package pgxiter_test
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.teddydd.me/iter/pgxiter"
+)
+
+func TestKeysetSQL(t *testing.T) {
+	tests := []struct {
+		name       string
+		keyset     pgxiter.Keyset
+		after      []any
+		expectSQL  string
+		expectArgs []any
+	}{
+		{
+			name: "first page",
+			keyset: pgxiter.Keyset{
+				Query: "SELECT id, name FROM users WHERE active = $1",
+				Args:  []any{true},
+				Keys:  []string{"id"},
+				Limit: 10,
+			},
+			expectSQL:  "SELECT * FROM (SELECT id, name FROM users WHERE active = $1) AS keyset ORDER BY id LIMIT $2",
+			expectArgs: []any{true, 10},
+		},
+		{
+			name: "composite key",
+			keyset: pgxiter.Keyset{
+				Query: "SELECT * FROM events",
+				Keys:  []string{"created_at", "id"},
+				Limit: 100,
+			},
+			after:      []any{"2023-01-01", 7},
+			expectSQL:  "SELECT * FROM (SELECT * FROM events) AS keyset WHERE (created_at, id) > ($1, $2) ORDER BY created_at, id LIMIT $3",
+			expectArgs: []any{"2023-01-01", 7, 100},
+		},
+		{
+			name: "descending",
+			keyset: pgxiter.Keyset{
+				Query:      "SELECT * FROM events WHERE kind = $1",
+				Args:       []any{"login"},
+				Keys:       []string{"created_at", "id"},
+				Descending: true,
+				Limit:      5,
+			},
+			after:      []any{"2023-01-01", 7},
+			expectSQL:  "SELECT * FROM (SELECT * FROM events WHERE kind = $1) AS keyset WHERE (created_at, id) < ($2, $3) ORDER BY created_at DESC, id DESC LIMIT $4",
+			expectArgs: []any{"login", "2023-01-01", 7, 5},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sql, args := tc.keyset.SQL(tc.after)
+			if sql != tc.expectSQL {
+				t.Errorf("unexpected sql:\n got: %s\nwant: %s", sql, tc.expectSQL)
+			}
+			if !reflect.DeepEqual(args, tc.expectArgs) {
+				t.Errorf("unexpected args: got %+v, want %+v", args, tc.expectArgs)
+			}
+		})
+	}
+}
+
+// fakeDB serves ids 1..count and honors the keyset WHERE and LIMIT.
+type fakeDB struct {
+	count int
+}
+
+var placeholder = regexp.MustCompile(`WHERE \(id\) > \(\$1\)`)
+
+func (db fakeDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	after := 0
+	if placeholder.MatchString(sql) {
+		after = args[0].(int)
+	}
+	limit := args[len(args)-1].(int)
+
+	rows := &fakeRows{}
+	for id := after + 1; id <= db.count && len(rows.ids) < limit; id++ {
+		rows.ids = append(rows.ids, id)
+	}
+	return rows, nil
+}
+
+type fakeRows struct {
+	ids     []int
+	current int
+}
+
+func (r *fakeRows) Close()                                       {}
+func (r *fakeRows) Err() error                                   { return nil }
+func (r *fakeRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *fakeRows) RawValues() [][]byte                          { return nil }
+func (r *fakeRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *fakeRows) Next() bool {
+	if len(r.ids) == 0 {
+		return false
+	}
+	r.current, r.ids = r.ids[0], r.ids[1:]
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	*dest[0].(*int) = r.current
+	return nil
+}
+
+func (r *fakeRows) Values() ([]any, error) {
+	return []any{r.current}, nil
+}
+
+func TestNew(t *testing.T) {
+	iterator := pgxiter.New(context.Background(), fakeDB{count: 5}, pgxiter.Config[int]{
+		Keyset: pgxiter.Keyset{
+			Query: "SELECT id FROM users",
+			Keys:  []string{"id"},
+			Limit: 2,
+		},
+		Scan: pgx.RowTo[int],
+		Key: func(id int) []any {
+			return []any{id}
+		},
+	})
+
+	var pages [][]int
+	err := iterator.Iterate(func(response []int) error {
+		pages = append(pages, response)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(pages, [][]int{{1, 2}, {3, 4}, {5}}) {
+		t.Errorf("unexpected pages: %+v", pages)
+	}
+}
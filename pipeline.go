@@ -0,0 +1,68 @@
+package iter
+
+import "context"
+
+// Pipeline assembles stream combinators and a [BatchSink] into a flow
+// run with a single call:
+//
+//	err := iter.Pipe(iter.Items(cursor)).
+//		Filter(active).
+//		Map(4, enrich).
+//		Chunk(500).
+//		To(sink).
+//		Run(ctx)
+//
+// Stages changing the type of values are applied to the stream before
+// passing it to [Pipe].
+type Pipeline[T any] struct {
+	stream *Stream[T]
+	size   int
+	sink   BatchSink[T]
+}
+
+// Pipe starts a pipeline consuming the stream.
+func Pipe[T any](stream *Stream[T]) *Pipeline[T] {
+	return &Pipeline[T]{stream: stream, size: 1}
+}
+
+// Map transforms values with f, see [Map].
+func (p *Pipeline[T]) Map(workers int, f func(value T) (T, error)) *Pipeline[T] {
+	p.stream = Map(p.stream, workers, f)
+	return p
+}
+
+// Filter keeps only values for which keep returns true.
+func (p *Pipeline[T]) Filter(keep func(value T) bool) *Pipeline[T] {
+	p.stream = filter(p.stream, keep, func() {})
+	return p
+}
+
+// Until stops the pipeline before the first value for which stop
+// returns true, see [Until].
+func (p *Pipeline[T]) Until(stop func(value T) bool) *Pipeline[T] {
+	p.stream = Until(p.stream, stop)
+	return p
+}
+
+// Chunk sets the number of values written to the sink at once.
+func (p *Pipeline[T]) Chunk(size int) *Pipeline[T] {
+	p.size = size
+	return p
+}
+
+// To sets the sink of the pipeline.
+func (p *Pipeline[T]) To(sink BatchSink[T]) *Pipeline[T] {
+	p.sink = sink
+	return p
+}
+
+// Run drains the pipeline into its sink, see [Drain]. Without a sink,
+// values are discarded, e.g. for pipelines run for side effects of Map.
+func (p *Pipeline[T]) Run(ctx context.Context) error {
+	if p.sink == nil {
+		return p.stream.Iterate(func(T) error {
+			return ctx.Err()
+		})
+	}
+	return Drain(ctx, p.stream, p.sink, p.size)
+}
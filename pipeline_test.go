@@ -0,0 +1,32 @@
+package iter_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestPipeline(t *testing.T) {
+	pages := [][]int{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}
+	sink := &batchRecorder{}
+
+	err := iter.Pipe(iter.Items(iter.New(pagesConfig(pages)))).
+		Filter(func(value int) bool { return value%2 == 1 }).
+		Map(2, func(value int) (int, error) { return value * 10, nil }).
+		Until(func(value int) bool { return value > 70 }).
+		Chunk(2).
+		To(sink).
+		Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := [][]int{{10, 30}, {50, 70}}; !reflect.DeepEqual(sink.batches, expected) {
+		t.Errorf("unexpected batches: %v", sink.batches)
+	}
+	if !sink.flushed {
+		t.Error("sink not flushed")
+	}
+}
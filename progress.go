@@ -0,0 +1,176 @@
+package iter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Progress describes how far an iteration got.
+type Progress struct {
+	// Pages is the number of fetched results.
+	Pages int
+	// Items is the number of fetched items.
+	Items int
+	// Total is the expected number of items, zero if unknown.
+	Total int
+	// Bytes is the number of fetched bytes, zero if not counted.
+	Bytes   int64
+	Elapsed time.Duration
+	// Done reports whether the cursor is depleted.
+	Done bool
+}
+
+// Rate returns fetched items per second.
+func (p Progress) Rate() float64 {
+	if p.Elapsed <= 0 {
+		return 0
+	}
+	return float64(p.Items) / p.Elapsed.Seconds()
+}
+
+// String formats progress for a terminal, e.g.
+// "1200/5000 items (24%), 3.4 MiB, 120.0 items/s".
+func (p Progress) String() string {
+	var b strings.Builder
+	if p.Total > 0 {
+		fmt.Fprintf(&b, "%d/%d items (%d%%)", p.Items, p.Total, p.Items*100/p.Total)
+	} else {
+		fmt.Fprintf(&b, "%d items", p.Items)
+	}
+	if p.Bytes > 0 {
+		fmt.Fprintf(&b, ", %s", formatBytes(p.Bytes))
+	}
+	fmt.Fprintf(&b, ", %.1f items/s", p.Rate())
+	return b.String()
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, prefix := float64(n)/unit, 0
+	for value >= unit && prefix < 4 {
+		value /= unit
+		prefix++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, "KMGTP"[prefix])
+}
+
+// ProgressConfig configures [TrackProgress].
+type ProgressConfig[Result any] struct {
+	// Items returns the number of items in the result.
+	Items func(result Result) int
+	// Total is optional. It returns the expected number of items
+	// reported by the result, or zero if unknown.
+	Total func(result Result) int
+	// Bytes is optional. It returns the number of bytes fetched so far,
+	// e.g. from [HTTPStats].
+	Bytes func() int64
+	// Report is called with the progress after fetches, at most once per
+	// Interval, and always once the cursor is depleted.
+	Report   func(progress Progress)
+	Interval time.Duration
+	// Clock is used for timing, SystemClock if nil.
+	Clock Clock
+}
+
+// TrackProgress returns middleware reporting progress of the iteration,
+// e.g. to draw a progress bar of a command line tool with
+// [ProgressWriter].
+func TrackProgress[Input, Result any](config ProgressConfig[Result]) Middleware[Input, Result] {
+	clock := config.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	return func(c Config[Input, Result]) Config[Input, Result] {
+		var (
+			progress Progress
+			before   Progress
+			refetch  bool
+			started  time.Time
+			reported time.Time
+		)
+
+		report := func() {
+			now := clock.Now()
+			if !progress.Done && now.Sub(reported) < config.Interval {
+				return
+			}
+			reported = now
+			progress.Elapsed = now.Sub(started)
+			if config.Bytes != nil {
+				progress.Bytes = config.Bytes()
+			}
+			config.Report(progress)
+		}
+
+		getFirstInput := c.GetFirstInput
+		c.GetFirstInput = func() Input {
+			progress, started, reported = Progress{}, time.Time{}, time.Time{}
+			refetch = false
+			return getFirstInput()
+		}
+
+		c.onRefetch(func() {
+			refetch = true
+		})
+
+		c.wrapFetch(func(fetchNext func(input Input) (Result, error)) func(input Input) (Result, error) {
+			return func(input Input) (Result, error) {
+				if started.IsZero() {
+					started = clock.Now()
+				}
+				base := progress
+				if refetch {
+					base, refetch = before, false
+				}
+				result, err := fetchNext(input)
+				if err == nil {
+					before, progress = base, base
+					progress.Pages++
+					progress.Items += config.Items(result)
+					if config.Total != nil {
+						if total := config.Total(result); total > 0 {
+							progress.Total = total
+						}
+					}
+				}
+				return result, err
+			}
+		})
+
+		hasNext := c.HasNext
+		c.HasNext = func(result Result) (Input, bool) {
+			input, ok := hasNext(result)
+			progress.Done = !ok
+			report()
+			return input, ok
+		}
+
+		return c
+	}
+}
+
+// ProgressWriter returns a Report function of [ProgressConfig] redrawing
+// the progress on a single terminal line of w.
+func ProgressWriter(w io.Writer) func(progress Progress) {
+	var width int
+	return func(progress Progress) {
+		line := progress.String()
+		padding := ""
+		if len(line) < width {
+			padding = strings.Repeat(" ", width-len(line))
+		}
+		width = len(line)
+
+		end := ""
+		if progress.Done {
+			end = "\n"
+		}
+		fmt.Fprintf(w, "\r%s%s%s", line, padding, end)
+	}
+}
@@ -0,0 +1,76 @@
+package iter_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.teddydd.me/iter"
+	"go.teddydd.me/iter/itertest"
+)
+
+func TestTrackProgress(t *testing.T) {
+	clock := itertest.NewClock(time.Now())
+
+	var reports []iter.Progress
+	config := pagesConfig([][]int{{1, 2}, {3}, {4, 5, 6}, {7}})
+	fetchNext := config.FetchNext
+	config.FetchNext = func(input int) ([]int, error) {
+		clock.Advance(time.Second)
+		return fetchNext(input)
+	}
+	tracked := iter.Use(config, iter.TrackProgress[int, []int](iter.ProgressConfig[[]int]{
+		Items: func(page []int) int { return len(page) },
+		Total: func(page []int) int { return 8 },
+		Bytes: func() int64 { return 3 << 20 },
+		Report: func(progress iter.Progress) {
+			reports = append(reports, progress)
+		},
+		Interval: 2 * time.Second,
+		Clock:    clock,
+	}))
+
+	if err := iter.New(tracked).Iterate(func([]int) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	// reported after the first fetch, then every two seconds and at the end
+	if len(reports) != 3 {
+		t.Fatalf("unexpected reports: %+v", reports)
+	}
+	last := reports[2]
+	if !last.Done || last.Items != 7 || last.Pages != 4 || last.Elapsed != 4*time.Second {
+		t.Errorf("unexpected last report: %+v", last)
+	}
+	if got, want := last.String(), "7/8 items (87%), 3.0 MiB, 1.8 items/s"; got != want {
+		t.Errorf("unexpected string: got %q, want %q", got, want)
+	}
+}
+
+func TestProgressWriter(t *testing.T) {
+	var b strings.Builder
+	write := iter.ProgressWriter(&b)
+	write(iter.Progress{Items: 1000, Elapsed: time.Second})
+	write(iter.Progress{Items: 1, Elapsed: time.Second, Done: true})
+
+	expected := "\r1000 items, 1000.0 items/s\r1 items, 1.0 items/s      \n"
+	if b.String() != expected {
+		t.Errorf("unexpected output: %q", b.String())
+	}
+}
+
+func TestTrackProgressRetry(t *testing.T) {
+	var last iter.Progress
+	tracked := iter.Use(pagesConfig([][]int{{1, 2}, {3}}), iter.TrackProgress[int, []int](iter.ProgressConfig[[]int]{
+		Items:  func(page []int) int { return len(page) },
+		Report: func(progress iter.Progress) { last = progress },
+		Clock:  itertest.NewClock(time.Now()),
+	}))
+
+	if _, err := collectRetrying(iter.New(tracked)); err != nil {
+		t.Fatal(err)
+	}
+	if last.Pages != 2 || last.Items != 3 || !last.Done {
+		t.Errorf("unexpected progress: %+v", last)
+	}
+}
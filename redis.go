@@ -0,0 +1,59 @@
+package iter
+
+// ScanConfig configures iteration over Redis SCAN family commands.
+type ScanConfig struct {
+	// Scan runs SCAN, SSCAN, HSCAN or ZSCAN starting at the cursor and
+	// returns the items and the next cursor. With go-redis it is
+	// client.Scan(ctx, cursor, match, count).Result().
+	Scan func(cursor uint64) (items []string, next uint64, err error)
+	// Dedup drops items already returned during the iteration, since
+	// SCAN commands may return an element more than once.
+	Dedup bool
+	// Pairs should be set for HSCAN and ZSCAN, which return field and
+	// value pairs. Duplicates are then detected by field.
+	Pairs bool
+}
+
+// NewScan creates a cursor over Redis SCAN family commands. The cursor
+// returned by Redis is used as input, and the iteration ends when it
+// returns to 0.
+func NewScan(config ScanConfig) *Cursor[uint64, Page[uint64, string]] {
+	var seen map[string]struct{}
+
+	dedup := func(items []string) []string {
+		step := 1
+		if config.Pairs {
+			step = 2
+		}
+
+		unique := items[:0]
+		for i := 0; i+step <= len(items); i += step {
+			if _, ok := seen[items[i]]; ok {
+				continue
+			}
+			seen[items[i]] = struct{}{}
+			unique = append(unique, items[i:i+step]...)
+		}
+		return unique
+	}
+
+	return New(Config[uint64, Page[uint64, string]]{
+		HasNext: func(result Page[uint64, string]) (uint64, bool) {
+			return result.Next, result.Next != 0
+		},
+		FetchNext: func(input uint64) (Page[uint64, string], error) {
+			items, next, err := config.Scan(input)
+			if err != nil {
+				return Page[uint64, string]{}, err
+			}
+			if config.Dedup {
+				items = dedup(items)
+			}
+			return Page[uint64, string]{Items: items, Next: next}, nil
+		},
+		GetFirstInput: func() uint64 {
+			seen = make(map[string]struct{})
+			return 0
+		},
+	})
+}
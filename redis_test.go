@@ -0,0 +1,78 @@
+package iter_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+// fakeScan simulates SCAN returning pages of items by cursor.
+func fakeScan(pages map[uint64]iter.Page[uint64, string]) func(cursor uint64) ([]string, uint64, error) {
+	return func(cursor uint64) ([]string, uint64, error) {
+		page, ok := pages[cursor]
+		if !ok {
+			return nil, 0, errors.New("ERR invalid cursor")
+		}
+		return append([]string{}, page.Items...), page.Next, nil
+	}
+}
+
+func TestNewScan(t *testing.T) {
+	scan := fakeScan(map[uint64]iter.Page[uint64, string]{
+		0:  {Items: []string{"a", "b"}, Next: 17},
+		17: {Items: []string{"b", "c"}, Next: 5},
+		5:  {Items: []string{"d"}, Next: 0},
+	})
+
+	collectKeys := func(iterator *iter.Cursor[uint64, iter.Page[uint64, string]]) []string {
+		var keys []string
+		err := iterator.Iterate(func(response iter.Page[uint64, string]) error {
+			keys = append(keys, response.Items...)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return keys
+	}
+
+	if keys := collectKeys(iter.NewScan(iter.ScanConfig{Scan: scan})); !reflect.DeepEqual(keys, []string{"a", "b", "b", "c", "d"}) {
+		t.Errorf("unexpected keys: %+v", keys)
+	}
+
+	iterator := iter.NewScan(iter.ScanConfig{Scan: scan, Dedup: true})
+	expected := []string{"a", "b", "c", "d"}
+	if keys := collectKeys(iterator); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("unexpected deduplicated keys: %+v", keys)
+	}
+
+	iterator.Reset()
+	if keys := collectKeys(iterator); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("unexpected deduplicated keys after reset: %+v", keys)
+	}
+}
+
+func TestNewScanPairs(t *testing.T) {
+	iterator := iter.NewScan(iter.ScanConfig{
+		Scan: fakeScan(map[uint64]iter.Page[uint64, string]{
+			0: {Items: []string{"f1", "1", "f2", "1"}, Next: 3},
+			3: {Items: []string{"f2", "1", "f3", "2"}, Next: 0},
+		}),
+		Dedup: true,
+		Pairs: true,
+	})
+
+	var items []string
+	err := iterator.Iterate(func(response iter.Page[uint64, string]) error {
+		items = append(items, response.Items...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(items, []string{"f1", "1", "f2", "1", "f3", "2"}) {
+		t.Errorf("unexpected items: %+v", items)
+	}
+}
@@ -0,0 +1,168 @@
+package iter
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// CursorInfo describes the state of a cursor in a [Registry].
+type CursorInfo struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+	// Pages is the number of successfully fetched results.
+	Pages int `json:"pages"`
+	// Input is the input of the last fetch, formatted with fmt or
+	// [Config.Redact].
+	Input     string    `json:"input"`
+	LastError string    `json:"last_error,omitempty"`
+	Started   time.Time `json:"started"`
+	LastFetch time.Time `json:"last_fetch"`
+	Done      bool      `json:"done"`
+}
+
+// Registry lists running cursors, so operators can see what long
+// running iterations are doing. It is safe for concurrent use.
+type Registry struct {
+	mu      sync.Mutex
+	cursors map[*CursorInfo]struct{}
+	clock   Clock
+}
+
+// DefaultRegistry is the registry used by [Register] with nil registry.
+var DefaultRegistry = NewRegistry(nil)
+
+// NewRegistry creates an empty registry timestamping fetches with clock,
+// SystemClock if nil.
+func NewRegistry(clock Clock) *Registry {
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &Registry{cursors: make(map[*CursorInfo]struct{}), clock: clock}
+}
+
+// Register returns middleware adding the cursor to the registry under
+// name, and function removing it from the registry. Name and Labels of
+// the config are used if name is empty. DefaultRegistry is used if
+// registry is nil.
+func Register[Input, Result any](registry *Registry, name string) (Middleware[Input, Result], func()) {
+	if registry == nil {
+		registry = DefaultRegistry
+	}
+
+	info := &CursorInfo{Name: name, Started: registry.clock.Now()}
+	registry.mu.Lock()
+	registry.cursors[info] = struct{}{}
+	registry.mu.Unlock()
+
+	unregister := func() {
+		registry.mu.Lock()
+		delete(registry.cursors, info)
+		registry.mu.Unlock()
+	}
+
+	return func(config Config[Input, Result]) Config[Input, Result] {
+		if name == "" {
+			registry.mu.Lock()
+			info.Name, info.Labels = config.Name, config.Labels
+			registry.mu.Unlock()
+		}
+
+		var (
+			before  int
+			refetch bool
+		)
+
+		getFirstInput := config.GetFirstInput
+		config.GetFirstInput = func() Input {
+			registry.mu.Lock()
+			info.Pages, info.Done, info.LastError = 0, false, ""
+			refetch = false
+			registry.mu.Unlock()
+			return getFirstInput()
+		}
+
+		config.onRefetch(func() {
+			registry.mu.Lock()
+			refetch = true
+			registry.mu.Unlock()
+		})
+
+		config.wrapFetch(func(fetchNext func(input Input) (Result, error)) func(input Input) (Result, error) {
+			return func(input Input) (Result, error) {
+				registry.mu.Lock()
+				pages := info.Pages
+				if refetch {
+					pages, refetch = before, false
+				}
+				if config.Redact != nil {
+					info.Input = config.Redact(input)
+				} else {
+					info.Input = fmt.Sprint(input)
+				}
+				registry.mu.Unlock()
+
+				result, err := fetchNext(input)
+
+				registry.mu.Lock()
+				info.LastFetch = registry.clock.Now()
+				if err != nil {
+					info.LastError = err.Error()
+				} else {
+					before, info.Pages = pages, pages+1
+				}
+				registry.mu.Unlock()
+				return result, err
+			}
+		})
+
+		hasNext := config.HasNext
+		config.HasNext = func(result Result) (Input, bool) {
+			input, ok := hasNext(result)
+			registry.mu.Lock()
+			info.Done = !ok
+			registry.mu.Unlock()
+			return input, ok
+		}
+
+		return config
+	}, unregister
+}
+
+// List returns registered cursors sorted by name.
+func (r *Registry) List() []CursorInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list := make([]CursorInfo, 0, len(r.cursors))
+	for info := range r.cursors {
+		list = append(list, *info)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Name != list[j].Name {
+			return list[i].Name < list[j].Name
+		}
+		return list[i].Started.Before(list[j].Started)
+	})
+	return list
+}
+
+// ServeHTTP responds with the list of registered cursors as JSON, so the
+// registry can be mounted as a debug handler.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(r.List())
+}
+
+// Var returns the registry as an expvar variable, e.g.
+//
+//	expvar.Publish("cursors", iter.DefaultRegistry.Var())
+func (r *Registry) Var() expvar.Var {
+	return expvar.Func(func() any {
+		return r.List()
+	})
+}
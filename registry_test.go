@@ -0,0 +1,106 @@
+package iter_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.teddydd.me/iter"
+	"go.teddydd.me/iter/itertest"
+)
+
+func TestRegistry(t *testing.T) {
+	started := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := itertest.NewClock(started)
+	registry := iter.NewRegistry(clock)
+
+	track, unregister := iter.Register[int, []int](registry, "export")
+	iterator := iter.New(iter.Use(pagesConfig([][]int{{1}, {2}, {3}}), track))
+
+	clock.Advance(time.Minute)
+	if _, err := iterator.Get(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	list := registry.List()
+	if len(list) != 1 {
+		t.Fatalf("expected one cursor, got %+v", list)
+	}
+	if info := list[0]; info.Name != "export" || info.Pages != 1 || info.Input != "0" || info.Done {
+		t.Errorf("unexpected info: %+v", info)
+	}
+	if info := list[0]; !info.Started.Equal(started) || !info.LastFetch.Equal(started.Add(time.Minute)) {
+		t.Errorf("unexpected timestamps: started %v, last fetch %v", info.Started, info.LastFetch)
+	}
+
+	iterator.Iterate(func([]int) error { return nil })
+
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest("GET", "/debug/cursors", nil))
+	var served []iter.CursorInfo
+	if err := json.NewDecoder(recorder.Body).Decode(&served); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(served) != 1 || served[0].Pages != 3 || !served[0].Done {
+		t.Errorf("unexpected served info: %+v", served)
+	}
+
+	if registry.Var().String() == "" {
+		t.Errorf("expected expvar value")
+	}
+
+	unregister()
+	if list := registry.List(); len(list) != 0 {
+		t.Errorf("expected no cursors, got %+v", list)
+	}
+}
+
+func TestRegistryLastError(t *testing.T) {
+	registry := iter.NewRegistry(nil)
+	track, unregister := iter.Register[int, []int](registry, "failing")
+	defer unregister()
+
+	config := pagesConfig([][]int{{1}})
+	config.FetchNext = func(int) ([]int, error) {
+		return nil, errors.New("boom")
+	}
+	iter.New(iter.Use(config, track)).Get()
+
+	if info := registry.List()[0]; info.LastError != "boom" || info.Pages != 0 {
+		t.Errorf("unexpected info: %+v", info)
+	}
+}
+
+func TestRegistryConfigName(t *testing.T) {
+	registry := iter.NewRegistry(nil)
+	config := pagesConfig([][]int{{1}})
+	config.Name = "invoice-backfill"
+	config.Labels = map[string]string{"tenant": "acme"}
+
+	track, unregister := iter.Register[int, []int](registry, "")
+	defer unregister()
+	cursor := iter.New(iter.Use(config, track))
+	if cursor.Name() != "invoice-backfill" || cursor.Labels()["tenant"] != "acme" {
+		t.Errorf("unexpected cursor identity: %q %v", cursor.Name(), cursor.Labels())
+	}
+
+	list := registry.List()
+	if len(list) != 1 || list[0].Name != "invoice-backfill" || list[0].Labels["tenant"] != "acme" {
+		t.Errorf("unexpected info: %+v", list)
+	}
+}
+
+func TestRegistryRetry(t *testing.T) {
+	registry := iter.NewRegistry(itertest.NewClock(time.Now()))
+	track, unregister := iter.Register[int, []int](registry, "export")
+	defer unregister()
+
+	if _, err := collectRetrying(iter.New(iter.Use(pagesConfig([][]int{{1}, {2}}), track))); err != nil {
+		t.Fatal(err)
+	}
+	if list := registry.List(); len(list) != 1 || list[0].Pages != 2 {
+		t.Errorf("unexpected info: %+v", list)
+	}
+}
@@ -0,0 +1,57 @@
+package iter
+
+// Result is a value or the error that prevented producing it. Streams
+// of Results carry per-item errors, e.g. decoding failures, downstream
+// instead of aborting the iteration.
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// TryMap returns stream of Results of applying f to values of the
+// stream. Errors returned by f are yielded as failed Results, while
+// errors of the stream itself abort the iteration as usual.
+func TryMap[T, U any](stream *Stream[T], f func(value T) (U, error)) *Stream[Result[U]] {
+	return newStream(func() (Result[U], error) {
+		value, err := stream.Get()
+		if err != nil {
+			return Result[U]{}, err
+		}
+
+		mapped, err := f(value)
+		return Result[U]{Value: mapped, Err: err}, nil
+	}, stream.Reset)
+}
+
+// Values returns stream of values of successful Results. Failed Results
+// are passed to onError; returning an error from it aborts the stream,
+// returning nil skips the failed Result.
+func Values[T any](stream *Stream[Result[T]], onError func(err error) error) *Stream[T] {
+	return newStream(func() (T, error) {
+		for {
+			result, err := stream.Get()
+			if err != nil {
+				return result.Value, err
+			}
+			if result.Err == nil {
+				return result.Value, nil
+			}
+			if err := onError(result.Err); err != nil {
+				var zero T
+				return zero, err
+			}
+		}
+	}, stream.Reset)
+}
+
+// Errors returns stream of errors of failed Results.
+func Errors[T any](stream *Stream[Result[T]]) *Stream[error] {
+	return newStream(func() (error, error) {
+		for {
+			result, err := stream.Get()
+			if err != nil || result.Err != nil {
+				return result.Err, err
+			}
+		}
+	}, stream.Reset)
+}
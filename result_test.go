@@ -0,0 +1,63 @@
+package iter_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func decoded() *iter.Stream[iter.Result[int]] {
+	raw := iter.Items(iter.New(pagesConfig([][]string{{"1", "x"}, {"3", "y", "5"}})))
+	return iter.TryMap(raw, strconv.Atoi)
+}
+
+func TestTryMap(t *testing.T) {
+	results := collect(t, decoded())
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got %+v", results)
+	}
+	for i, failed := range []bool{false, true, false, true, false} {
+		if (results[i].Err != nil) != failed {
+			t.Errorf("unexpected result %d: %+v", i, results[i])
+		}
+	}
+}
+
+func TestValues(t *testing.T) {
+	var skipped []error
+	values := collect(t, iter.Values(decoded(), func(err error) error {
+		skipped = append(skipped, err)
+		return nil
+	}))
+	if !reflect.DeepEqual(values, []int{1, 3, 5}) {
+		t.Errorf("unexpected values: %v", values)
+	}
+	if len(skipped) != 2 {
+		t.Errorf("expected 2 skipped errors, got %v", skipped)
+	}
+
+	failure := errors.New("failure")
+	values, err := iter.Values(decoded(), func(err error) error {
+		return failure
+	}).Collect()
+	if !errors.Is(err, failure) {
+		t.Errorf("expected failure, got %v", err)
+	}
+	if !reflect.DeepEqual(values, []int{1}) {
+		t.Errorf("unexpected values: %v", values)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	errs := collect(t, iter.Errors(decoded()))
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	var numErr *strconv.NumError
+	if !errors.As(errs[0], &numErr) || numErr.Num != "x" {
+		t.Errorf("unexpected error: %v", errs[0])
+	}
+}
@@ -0,0 +1,92 @@
+package iter
+
+import "encoding/json"
+
+// ResumeToken is the position in the item-level iteration: the input
+// of the page and the number of its items already consumed. Its input
+// is encoded in JSON like in [Checkpoint], with registered codecs and
+// versions.
+type ResumeToken[Input any] struct {
+	Input  Input
+	Offset int
+	// Fresh reports that the page is the first one, so resuming starts
+	// the cursor over, see [Checkpoint].
+	Fresh bool
+}
+
+type jsonResumeToken[Input any] struct {
+	Page   Checkpoint[Input] `json:"page"`
+	Offset int               `json:"offset"`
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (t ResumeToken[Input]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonResumeToken[Input]{
+		Page:   Checkpoint[Input]{Input: t.Input, Fresh: t.Fresh},
+		Offset: t.Offset,
+	})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (t *ResumeToken[Input]) UnmarshalJSON(data []byte) error {
+	var token jsonResumeToken[Input]
+	if err := json.Unmarshal(data, &token); err != nil {
+		return err
+	}
+	t.Input, t.Offset, t.Fresh = token.Page.Input, token.Offset, token.Page.Fresh
+	return nil
+}
+
+// Resumable is an item along with the token resuming the iteration
+// right after it.
+type Resumable[Input, T any] struct {
+	Item  T
+	Token ResumeToken[Input]
+}
+
+// ResumeItems returns stream of items of pages fetched by the cursor
+// along with resume tokens, so a consumer crashing halfway through a
+// page resumes mid-page rather than reprocessing it. If token is not
+// nil, the iteration starts from it: its page is fetched again and
+// already consumed items are skipped. Reset starts over from the
+// beginning of the cursor.
+func ResumeItems[Input, T any](cursor *Cursor[Input, []T], token *ResumeToken[Input]) *Stream[Resumable[Input, T]] {
+	var (
+		page  []T
+		input Input
+		fresh bool
+		index int
+		skip  int
+	)
+
+	if token != nil {
+		cursor.Restore(Checkpoint[Input]{Input: token.Input, Fresh: token.Fresh})
+		skip = token.Offset
+	}
+
+	return newStream(func() (Resumable[Input, T], error) {
+		for index >= len(page) {
+			if !cursor.Next() {
+				return Resumable[Input, T]{}, ErrStop
+			}
+
+			fresh = cursor.fresh
+			result, err := cursor.Get()
+			if err != nil {
+				return Resumable[Input, T]{}, err
+			}
+			input = cursor.last
+
+			page, index, skip = result, skip, 0
+		}
+
+		index++
+		return Resumable[Input, T]{
+			Item:  page[index-1],
+			Token: ResumeToken[Input]{Input: input, Offset: index, Fresh: fresh},
+		}, nil
+	}, func() {
+		cursor.Reset()
+		page, index, skip = nil, 0, 0
+	})
+}
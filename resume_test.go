@@ -0,0 +1,99 @@
+package iter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestResumeItems(t *testing.T) {
+	crash := errors.New("crash")
+	pages := [][]int{{1, 2, 3}, {4, 5}, {6}}
+
+	var (
+		saved     []byte
+		processed []int
+	)
+	run := func(token *iter.ResumeToken[int], failAt int) error {
+		stream := iter.ResumeItems(iter.New(pagesConfig(pages)), token)
+		return stream.Iterate(func(item iter.Resumable[int, int]) error {
+			if item.Item == failAt {
+				return crash
+			}
+			processed = append(processed, item.Item)
+
+			var err error
+			saved, err = json.Marshal(item.Token)
+			return err
+		})
+	}
+
+	if err := run(nil, 5); !errors.Is(err, crash) {
+		t.Fatalf("expected crash, got %v", err)
+	}
+
+	var token iter.ResumeToken[int]
+	if err := json.Unmarshal(saved, &token); err != nil {
+		t.Fatal(err)
+	}
+	if token != (iter.ResumeToken[int]{Input: 1, Offset: 1}) {
+		t.Errorf("unexpected token: %+v", token)
+	}
+
+	if err := run(&token, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(processed, []int{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("unexpected processed items: %v", processed)
+	}
+}
+
+func TestResumeItemsFirstPage(t *testing.T) {
+	config := pagesConfig([][]int{{0, 1, 2}, {3}})
+	starts := 0
+	config.Start = func(ctx context.Context, input int) (int, error) {
+		starts++
+		return input, nil
+	}
+
+	item, err := iter.ResumeItems(iter.New(config), nil).Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !item.Token.Fresh {
+		t.Fatalf("expected fresh token of the first page, got %+v", item.Token)
+	}
+
+	values, err := iter.ResumeItems(iter.New(config), &item.Token).Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var items []int
+	for _, value := range values {
+		items = append(items, value.Item)
+	}
+	if !reflect.DeepEqual(items, []int{1, 2, 3}) || starts != 2 {
+		t.Errorf("unexpected resumed items: %v after %d starts", items, starts)
+	}
+}
+
+func TestResumeTokenCodec(t *testing.T) {
+	iter.RegisterCodec[offset](offsetCodec{})
+
+	data, err := json.Marshal(iter.ResumeToken[offset]{Input: offset{value: 42}, Offset: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var token iter.ResumeToken[offset]
+	if err := json.Unmarshal(data, &token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.Input.value != 42 || token.Offset != 3 || token.Fresh {
+		t.Errorf("expected token to round-trip, got %+v from %s", token, data)
+	}
+}
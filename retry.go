@@ -0,0 +1,100 @@
+package iter
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// Retry tells how many times and how soon a failed request is retried.
+type Retry struct {
+	// Attempts is the maximum number of retries.
+	Attempts int
+	// Backoff is the delay before the first retry, doubled by every
+	// following one. Longer Retry-After of the response takes
+	// precedence.
+	Backoff time.Duration
+}
+
+// RetryPolicy selects retries of failed requests of HTTP adapters by
+// the class of the failure. Failures without a retry are returned.
+type RetryPolicy struct {
+	// Status maps response status codes to their retries.
+	Status map[int]Retry
+	// Errors is the retry of requests failed without response.
+	Errors Retry
+	// Classify is optional. It returns the retry of the response, nil if
+	// the request failed with err, or false to use Status and Errors.
+	Classify func(resp *http.Response, err error) (Retry, bool)
+}
+
+// DefaultRetryPolicy retries requests failed without response and
+// responses with 502, 503 and 504 status three times, and 429 Too Many
+// Requests five times. Client errors such as 400 and 401 are never
+// retried.
+var DefaultRetryPolicy = &RetryPolicy{
+	Status: map[int]Retry{
+		http.StatusTooManyRequests:    {Attempts: 5, Backoff: time.Second},
+		http.StatusBadGateway:         {Attempts: 3, Backoff: time.Second},
+		http.StatusServiceUnavailable: {Attempts: 3, Backoff: time.Second},
+		http.StatusGatewayTimeout:     {Attempts: 3, Backoff: time.Second},
+	},
+	Errors: Retry{Attempts: 3, Backoff: time.Second},
+}
+
+// retry returns the retry of the response or error.
+func (p *RetryPolicy) retry(resp *http.Response, err error) Retry {
+	if p == nil {
+		return Retry{}
+	}
+	if p.Classify != nil {
+		if retry, ok := p.Classify(resp, err); ok {
+			return retry
+		}
+	}
+	if err != nil {
+		return p.Errors
+	}
+	if resp.StatusCode < 400 {
+		return Retry{}
+	}
+	return p.Status[resp.StatusCode]
+}
+
+// send sends the request, retrying failures according to the policy.
+func (p *RetryPolicy) send(client Doer, clock Clock, req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Do(req)
+		retry := p.retry(resp, err)
+		if attempt >= retry.Attempts {
+			return resp, err
+		}
+
+		delay := retry.Backoff << attempt
+		if resp != nil {
+			if after, ok := retryAfter(resp.Header); ok && after > delay {
+				delay = after
+			}
+			io.CopyN(io.Discard, resp.Body, drainLimit)
+			resp.Body.Close()
+		}
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+		clock.Sleep(delay)
+	}
+}
+
+// rewind returns copy of the request with fresh body, so it can be sent
+// again.
+func rewind(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
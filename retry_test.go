@@ -0,0 +1,109 @@
+package iter_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.teddydd.me/iter"
+	"go.teddydd.me/iter/itertest"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		policy   *iter.RetryPolicy
+		requests int32
+		status   int
+		waited   time.Duration
+	}{
+		{
+			name:     "retried status",
+			statuses: []int{503, 502, 200},
+			policy:   iter.DefaultRetryPolicy,
+			requests: 3,
+			waited:   3 * time.Second,
+		},
+		{
+			name:     "never retried status",
+			statuses: []int{401, 200},
+			policy:   iter.DefaultRetryPolicy,
+			requests: 1,
+			status:   401,
+		},
+		{
+			name:     "retries exhausted",
+			statuses: []int{504, 504, 504},
+			policy: &iter.RetryPolicy{
+				Status: map[int]iter.Retry{504: {Attempts: 2, Backoff: time.Second}},
+			},
+			requests: 3,
+			status:   504,
+			waited:   3 * time.Second,
+		},
+		{
+			name:     "retry after",
+			statuses: []int{429, 200},
+			policy:   iter.DefaultRetryPolicy,
+			requests: 2,
+			waited:   time.Minute,
+		},
+		{
+			name:     "classifier",
+			statuses: []int{400, 200},
+			policy: &iter.RetryPolicy{
+				Classify: func(resp *http.Response, err error) (iter.Retry, bool) {
+					if resp != nil && resp.Header.Get("X-Transient") != "" {
+						return iter.Retry{Attempts: 1}, true
+					}
+					return iter.Retry{}, false
+				},
+			},
+			requests: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int32
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tc.statuses[atomic.AddInt32(&requests, 1)-1]
+				if status == http.StatusTooManyRequests {
+					w.Header().Set("Retry-After", "60")
+				}
+				w.Header().Set("X-Transient", "1")
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				fmt.Fprint(w, `{"numbers": [1]}`)
+			}))
+			defer mockServer.Close()
+
+			start := time.Now()
+			clock := sleepingClock{itertest.NewClock(start)}
+			iterator := iter.NewHTTP(iter.HTTPConfig[numberPage]{
+				HTTP: iter.HTTP{Clock: clock, Retry: tc.policy},
+				URL:  mockServer.URL,
+			})
+
+			_, err := iterator.Get()
+			var statusErr *iter.StatusError
+			switch {
+			case tc.status == 0 && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tc.status != 0 && (!errors.As(err, &statusErr) || statusErr.StatusCode != tc.status):
+				t.Errorf("expected status %d, got %v", tc.status, err)
+			}
+			if n := atomic.LoadInt32(&requests); n != tc.requests {
+				t.Errorf("expected %d requests, got %d", tc.requests, n)
+			}
+			if waited := clock.Now().Sub(start); waited != tc.waited {
+				t.Errorf("expected to wait %v, waited %v", tc.waited, waited)
+			}
+		})
+	}
+}
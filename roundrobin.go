@@ -0,0 +1,146 @@
+package iter
+
+import (
+	"errors"
+	"time"
+)
+
+// Weighted is a stream along with its share in [WeightedRoundRobin].
+type Weighted[T any] struct {
+	Stream *Stream[T]
+	// Weight is the number of values taken from the stream per round.
+	Weight int
+	// Rate limits values taken from the stream per second, unlimited if
+	// zero.
+	Rate float64
+}
+
+// RoundRobin returns stream interleaving values of given streams, one
+// value from each stream in turn. Depleted streams are skipped, so a
+// single consumer can drain several streams without starving any of
+// them.
+func RoundRobin[T any](streams ...*Stream[T]) *Stream[T] {
+	weighted := make([]Weighted[T], len(streams))
+	for i, stream := range streams {
+		weighted[i] = Weighted[T]{Stream: stream, Weight: 1}
+	}
+
+	return WeightedRoundRobin(weighted...)
+}
+
+// WeightedRoundRobin returns stream interleaving values of given
+// streams proportionally to their weights. Values are spread smoothly
+// within a round, so a stream with weight 3 next to one with weight 1
+// yields a, a, b, a rather than a, a, a, b. Rates are respected as in
+// [FairSchedule].
+func WeightedRoundRobin[T any](streams ...Weighted[T]) *Stream[T] {
+	return FairSchedule(SystemClock, streams...)
+}
+
+// FairSchedule works like [WeightedRoundRobin], but streams with Rate
+// set take no more than Rate values per second, bursting up to one
+// second worth of values. Rate limited streams yield their turn to
+// others, and the schedule sleeps only when all remaining streams are
+// limited. When multiplexing per-tenant cursors, schedule their Pages
+// so that weights and rates apply to fetches, and one huge tenant can
+// not monopolize the shared fetch budget. Clock is used for timing,
+// SystemClock if nil.
+func FairSchedule[T any](clock Clock, streams ...Weighted[T]) *Stream[T] {
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	current := make([]int, len(streams))
+	depleted := make([]bool, len(streams))
+	tokens := make([]float64, len(streams))
+	refilled := make([]time.Time, len(streams))
+
+	burst := func(i int) float64 {
+		if streams[i].Rate < 1 {
+			return 1
+		}
+		return streams[i].Rate
+	}
+
+	fill := func() {
+		now := clock.Now()
+		for i, stream := range streams {
+			if stream.Rate <= 0 {
+				continue
+			}
+			if !refilled[i].IsZero() {
+				tokens[i] += now.Sub(refilled[i]).Seconds() * stream.Rate
+			} else {
+				tokens[i] = burst(i)
+			}
+			if tokens[i] > burst(i) {
+				tokens[i] = burst(i)
+			}
+			refilled[i] = now
+		}
+	}
+
+	// pick returns the stream to take value from, or the delay until
+	// some limited stream can be taken from.
+	pick := func() (int, time.Duration) {
+		fill()
+
+		total, best, wait := 0, -1, time.Duration(-1)
+		for i, stream := range streams {
+			if !depleted[i] && !stream.Stream.Next() {
+				depleted[i] = true
+			}
+			if depleted[i] {
+				continue
+			}
+			if stream.Rate > 0 && tokens[i] < 1 {
+				delay := time.Duration((1 - tokens[i]) / stream.Rate * float64(time.Second))
+				if wait == -1 || delay < wait {
+					wait = delay
+				}
+				continue
+			}
+			current[i] += stream.Weight
+			total += stream.Weight
+			if best == -1 || current[i] > current[best] {
+				best = i
+			}
+		}
+		if best != -1 {
+			current[best] -= total
+		}
+		return best, wait
+	}
+
+	return newStream(func() (T, error) {
+		for {
+			i, wait := pick()
+			if i == -1 {
+				if wait == -1 {
+					var zero T
+					return zero, ErrStop
+				}
+				clock.Sleep(wait)
+				continue
+			}
+
+			value, err := streams[i].Stream.Get()
+			if errors.Is(err, ErrStop) {
+				depleted[i] = true
+				continue
+			}
+			if streams[i].Rate > 0 {
+				tokens[i]--
+			}
+
+			return value, err
+		}
+	}, func() {
+		for i, stream := range streams {
+			stream.Stream.Reset()
+			current[i] = 0
+			depleted[i] = false
+			refilled[i] = time.Time{}
+		}
+	})
+}
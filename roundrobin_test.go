@@ -0,0 +1,104 @@
+package iter_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.teddydd.me/iter"
+	"go.teddydd.me/iter/itertest"
+)
+
+func collect[T any](t *testing.T, stream *iter.Stream[T]) []T {
+	t.Helper()
+
+	var results []T
+	err := stream.Iterate(func(value T) error {
+		results = append(results, value)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	return results
+}
+
+func TestRoundRobin(t *testing.T) {
+	stream := iter.RoundRobin(
+		iter.Items(iter.New(pagesConfig([][]int{{1, 2}, {3}}))),
+		iter.Items(iter.New(pagesConfig([][]int{{}, {10, 11, 12, 13}, {14}}))),
+	)
+
+	expected := []int{1, 10, 2, 11, 3, 12, 13, 14}
+	if results := collect(t, stream); !reflect.DeepEqual(results, expected) {
+		t.Errorf("unexpected results: got %+v, want %+v", results, expected)
+	}
+
+	stream.Reset()
+	if results := collect(t, stream); !reflect.DeepEqual(results, expected) {
+		t.Errorf("unexpected results after reset: got %+v, want %+v", results, expected)
+	}
+}
+
+func TestWeightedRoundRobin(t *testing.T) {
+	stream := iter.WeightedRoundRobin(
+		iter.Weighted[[]int]{
+			Stream: iter.Pages(iter.New(pagesConfig([][]int{{1}, {2}, {3}, {4}, {5}, {6}}))),
+			Weight: 3,
+		},
+		iter.Weighted[[]int]{
+			Stream: iter.Pages(iter.New(pagesConfig([][]int{{10}, {11}, {12}}))),
+			Weight: 1,
+		},
+	)
+
+	expected := [][]int{{1}, {2}, {10}, {3}, {4}, {5}, {11}, {6}, {12}}
+	if results := collect(t, stream); !reflect.DeepEqual(results, expected) {
+		t.Errorf("unexpected results: got %+v, want %+v", results, expected)
+	}
+}
+
+// sleepingClock is a fake clock advanced by sleeping.
+type sleepingClock struct {
+	*itertest.Clock
+}
+
+func (c sleepingClock) Sleep(d time.Duration) {
+	c.Advance(d)
+}
+
+func TestFairSchedule(t *testing.T) {
+	start := time.Now()
+	clock := sleepingClock{itertest.NewClock(start)}
+
+	stream := iter.FairSchedule[[]int](clock,
+		iter.Weighted[[]int]{
+			Stream: iter.Pages(iter.New(pagesConfig([][]int{{1}, {2}, {3}, {4}}))),
+			Weight: 3,
+			Rate:   1,
+		},
+		iter.Weighted[[]int]{
+			Stream: iter.Pages(iter.New(pagesConfig([][]int{{10}, {11}, {12}}))),
+			Weight: 1,
+		},
+	)
+
+	// the heavy tenant is limited to one page per second, the others
+	// take its turns meanwhile
+	expected := [][]int{{1}, {10}, {11}, {12}, {2}, {3}, {4}}
+	var results [][]int
+	for range expected {
+		page, err := stream.Get()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		results = append(results, page)
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("unexpected results: got %+v, want %+v", results, expected)
+	}
+	if elapsed := clock.Now().Sub(start); elapsed != 3*time.Second {
+		t.Errorf("expected 3s of waiting, got %v", elapsed)
+	}
+}
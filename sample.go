@@ -0,0 +1,47 @@
+package iter
+
+import "math/rand"
+
+// Sample returns stream yielding each value of the stream with
+// probability rate. Sampling is deterministic for given seed, including
+// after Reset.
+func Sample[T any](stream *Stream[T], rate float64, seed int64) *Stream[T] {
+	random := rand.New(rand.NewSource(seed))
+
+	return filter(stream, func(value T) bool {
+		return random.Float64() < rate
+	}, func() {
+		random.Seed(seed)
+	})
+}
+
+// EveryNth returns stream yielding the first and then every n-th value
+// of the stream. All values are yielded if n is less than 2.
+func EveryNth[T any](stream *Stream[T], n int) *Stream[T] {
+	if n < 1 {
+		n = 1
+	}
+	var i int
+
+	return filter(stream, func(value T) bool {
+		keep := i%n == 0
+		i++
+		return keep
+	}, func() {
+		i = 0
+	})
+}
+
+func filter[T any](stream *Stream[T], keep func(value T) bool, reset func()) *Stream[T] {
+	return newStream(func() (T, error) {
+		for {
+			value, err := stream.Get()
+			if err != nil || keep(value) {
+				return value, err
+			}
+		}
+	}, func() {
+		stream.Reset()
+		reset()
+	})
+}
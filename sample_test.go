@@ -0,0 +1,64 @@
+package iter_test
+
+import (
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func numbers(n, pageSize int) *iter.Stream[int] {
+	var pages [][]int
+	if n == 0 {
+		pages = [][]int{nil}
+	}
+	for i := 0; i < n; i += pageSize {
+		var page []int
+		for j := i; j < i+pageSize && j < n; j++ {
+			page = append(page, j)
+		}
+		pages = append(pages, page)
+	}
+
+	return iter.Items(iter.New(pagesConfig(pages)))
+}
+
+func TestSample(t *testing.T) {
+	stream := iter.Sample(numbers(1000, 7), 0.1, 42)
+
+	first := collect(t, stream)
+	if len(first) < 50 || len(first) > 150 {
+		t.Errorf("expected about 100 sampled values, got %d", len(first))
+	}
+
+	stream.Reset()
+	second := collect(t, stream)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("sampling should be deterministic for the same seed")
+	}
+
+	if all := collect(t, iter.Sample(numbers(10, 3), 1, 0)); len(all) != 10 {
+		t.Errorf("rate 1 should keep all values, got %+v", all)
+	}
+}
+
+func TestEveryNth(t *testing.T) {
+	stream := iter.EveryNth(numbers(10, 3), 3)
+
+	expected := []int{0, 3, 6, 9}
+	if results := collect(t, stream); !reflect.DeepEqual(results, expected) {
+		t.Errorf("unexpected results: got %+v, want %+v", results, expected)
+	}
+
+	stream.Reset()
+	if results := collect(t, stream); !reflect.DeepEqual(results, expected) {
+		t.Errorf("unexpected results after reset: got %+v, want %+v", results, expected)
+	}
+
+	for _, n := range []int{0, -1} {
+		results := collect(t, iter.EveryNth(numbers(4, 3), n))
+		if !reflect.DeepEqual(results, []int{0, 1, 2, 3}) {
+			t.Errorf("unexpected results for n = %d: %+v", n, results)
+		}
+	}
+}
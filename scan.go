@@ -0,0 +1,21 @@
+package iter
+
+// Scan returns stream of running accumulator values. It works like a
+// reduce, but yields the accumulator after each value of the stream,
+// e.g. cumulative totals after each page of [Pages].
+func Scan[T, Acc any](stream *Stream[T], initial Acc, step func(acc Acc, value T) Acc) *Stream[Acc] {
+	acc := initial
+
+	return newStream(func() (Acc, error) {
+		value, err := stream.Get()
+		if err != nil {
+			return acc, err
+		}
+
+		acc = step(acc, value)
+		return acc, nil
+	}, func() {
+		stream.Reset()
+		acc = initial
+	})
+}
@@ -0,0 +1,28 @@
+package iter_test
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestScan(t *testing.T) {
+	mockServer := httptest.NewServer(MockAPIHandler(5))
+	defer mockServer.Close()
+
+	stream := iter.Scan(iter.Pages(simpleIterator(mockServer)), 0, func(total int, response []Record) int {
+		return total + len(response)
+	})
+
+	expected := []int{2, 4, 5, 5}
+	if results := collect(t, stream); !reflect.DeepEqual(results, expected) {
+		t.Errorf("unexpected results: got %+v, want %+v", results, expected)
+	}
+
+	stream.Reset()
+	if results := collect(t, stream); !reflect.DeepEqual(results, expected) {
+		t.Errorf("unexpected results after reset: got %+v, want %+v", results, expected)
+	}
+}
@@ -0,0 +1,115 @@
+package iter
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrSequenceGap is reported when keys are missing between two
+	// consecutive items.
+	ErrSequenceGap = errors.New("gap in sequence")
+	// ErrSequenceDuplicate is reported when two consecutive items have
+	// the same key.
+	ErrSequenceDuplicate = errors.New("duplicate in sequence")
+	// ErrSequenceRegression is reported when an item has a lower key than
+	// the one before it.
+	ErrSequenceRegression = errors.New("sequence regression")
+)
+
+// Sequence describes ordering of items in an ordered stream.
+type Sequence[Item, Key any] struct {
+	// Key returns ordering key of the item.
+	Key func(item Item) Key
+	// Compare returns a negative number if a is before b, zero if they
+	// are equal and a positive number otherwise.
+	Compare func(a, b Key) int
+	// Gap is optional. It reports whether any keys are missing between
+	// consecutive keys a and b.
+	Gap func(a, b Key) bool
+	// Report is optional. It is called with every detected problem.
+	// Returning nil treats the problem as a warning and continues the
+	// iteration. If Report is nil, problems abort the iteration.
+	Report func(err error) error
+}
+
+// CheckSequence wraps config so fetched items are checked for gaps,
+// duplicates and regressions, including across page boundaries. A page
+// fetched again is checked against the page before it.
+func CheckSequence[Input, Item, Key any](
+	config Config[Input, []Item],
+	sequence Sequence[Item, Key],
+) Config[Input, []Item] {
+	var (
+		prev      Key
+		hasPrev   bool
+		before    Key
+		hasBefore bool
+		refetch   bool
+	)
+
+	check := func(prev Key, hasPrev bool, key Key) error {
+		if !hasPrev {
+			return nil
+		}
+
+		switch c := sequence.Compare(prev, key); {
+		case c > 0:
+			return fmt.Errorf("%w: %v after %v", ErrSequenceRegression, key, prev)
+		case c == 0:
+			return fmt.Errorf("%w: %v", ErrSequenceDuplicate, key)
+		case sequence.Gap != nil && sequence.Gap(prev, key):
+			return fmt.Errorf("%w: between %v and %v", ErrSequenceGap, prev, key)
+		}
+
+		return nil
+	}
+
+	getFirstInput := config.GetFirstInput
+	config.GetFirstInput = func() Input {
+		hasPrev, refetch = false, false
+		return getFirstInput()
+	}
+
+	config.onRefetch(func() {
+		refetch = true
+	})
+
+	config.wrapFetch(func(fetchNext func(input Input) ([]Item, error)) func(input Input) ([]Item, error) {
+		return func(input Input) ([]Item, error) {
+			again := refetch
+			refetch = false
+			result, err := fetchNext(input)
+			if err != nil {
+				return result, err
+			}
+
+			base, hasBase := prev, hasPrev
+			if again {
+				base, hasBase = before, hasBefore
+			}
+
+			// the page is committed only once all its items pass, so
+			// a failed page can be fetched again
+			last, hasLast := base, hasBase
+			for _, item := range result {
+				key := sequence.Key(item)
+				if err := check(last, hasLast, key); err != nil {
+					if sequence.Report == nil {
+						return result, err
+					}
+					if err := sequence.Report(err); err != nil {
+						return result, err
+					}
+				}
+				last, hasLast = key, true
+			}
+
+			before, hasBefore = base, hasBase
+			prev, hasPrev = last, hasLast
+			return result, nil
+		}
+	})
+
+	return config
+}
@@ -0,0 +1,121 @@
+package iter_test
+
+import (
+	"errors"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestCheckSequence(t *testing.T) {
+	sequence := iter.Sequence[int, int]{
+		Key:     func(item int) int { return item },
+		Compare: func(a, b int) int { return a - b },
+		Gap:     func(a, b int) bool { return b-a > 1 },
+	}
+
+	tests := []struct {
+		name      string
+		pages     [][]int
+		expectErr error
+	}{
+		{
+			name:  "ordered",
+			pages: [][]int{{1, 2}, {3, 4}, {5}},
+		},
+		{
+			name:      "gap across pages",
+			pages:     [][]int{{1, 2}, {4}},
+			expectErr: iter.ErrSequenceGap,
+		},
+		{
+			name:      "duplicate across pages",
+			pages:     [][]int{{1, 2}, {2, 3}},
+			expectErr: iter.ErrSequenceDuplicate,
+		},
+		{
+			name:      "regression within page",
+			pages:     [][]int{{1, 2, 0}},
+			expectErr: iter.ErrSequenceRegression,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			iterator := iter.New(iter.CheckSequence(pagesConfig(tc.pages), sequence))
+			err := iterator.Iterate(func(response []int) error { return nil })
+			if !errors.Is(err, tc.expectErr) {
+				t.Errorf("expected %+v, got %+v", tc.expectErr, err)
+			}
+
+			iterator.Reset()
+			err = iterator.Iterate(func(response []int) error { return nil })
+			if !errors.Is(err, tc.expectErr) {
+				t.Errorf("expected %+v after reset, got %+v", tc.expectErr, err)
+			}
+		})
+	}
+
+	t.Run("report as warnings", func(t *testing.T) {
+		var warnings []error
+		sequence := sequence
+		sequence.Report = func(err error) error {
+			warnings = append(warnings, err)
+			return nil
+		}
+		iterator := iter.New(iter.CheckSequence(pagesConfig([][]int{{1, 3}, {3}, {2}}), sequence))
+		if err := iterator.Iterate(func(response []int) error { return nil }); err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if len(warnings) != 3 {
+			t.Fatalf("expected 3 warnings, got %+v", warnings)
+		}
+		for i, expect := range []error{iter.ErrSequenceGap, iter.ErrSequenceDuplicate, iter.ErrSequenceRegression} {
+			if !errors.Is(warnings[i], expect) {
+				t.Errorf("expected warning %d to be %+v, got %+v", i, expect, warnings[i])
+			}
+		}
+	})
+}
+
+func TestCheckSequenceRetry(t *testing.T) {
+	sequence := iter.Sequence[int, int]{
+		Key:     func(item int) int { return item },
+		Compare: func(a, b int) int { return a - b },
+	}
+	cursor := iter.New(iter.CheckSequence(pagesConfig([][]int{{1, 2}, {3}, {4}}), sequence))
+	pages, err := collectRetrying(cursor)
+	if err != nil || len(pages) != 3 {
+		t.Errorf("expected retried pages, got %v, %v", pages, err)
+	}
+}
+
+func TestCheckSequenceFailedPage(t *testing.T) {
+	sequence := iter.Sequence[int, int]{
+		Key:     func(item int) int { return item },
+		Compare: func(a, b int) int { return a - b },
+	}
+	// the second page is served out of order once
+	config := pagesConfig([][]int{{1, 2}, {3, 4}})
+	fetchNext := config.FetchNext
+	broken := true
+	config.FetchNext = func(input int) ([]int, error) {
+		page, err := fetchNext(input)
+		if input == 1 && broken {
+			broken = false
+			return []int{3, 0}, err
+		}
+		return page, err
+	}
+	cursor := iter.New(iter.CheckSequence(config, sequence))
+
+	if _, err := cursor.Get(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cursor.Get(); !errors.Is(err, iter.ErrSequenceRegression) {
+		t.Fatalf("expected regression, got %v", err)
+	}
+	if page, err := cursor.Get(); err != nil || page[0] != 3 {
+		t.Errorf("expected the page to pass when fetched again, got %v, %v", page, err)
+	}
+}
@@ -0,0 +1,153 @@
+package iter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Shard is a named cursor of a sharded iteration.
+type Shard[Input, Result any] struct {
+	Name   string
+	Cursor *Cursor[Input, Result]
+}
+
+// ShardError is a failure of a shard run by [RunShards].
+type ShardError struct {
+	Shard string
+	// Input is the input of the page that failed to be fetched or
+	// processed, redacted with [Config.Redact] if set.
+	Input any
+	Err   error
+}
+
+func (e *ShardError) Error() string {
+	return fmt.Sprintf("shard %s at %v: %v", e.Shard, e.Input, e.Err)
+}
+
+func (e *ShardError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError holds failures of several shards, in order of the shards.
+type MultiError struct {
+	Errors []error
+}
+
+func (e *MultiError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d failed: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
+
+// RunShards iterates the shards with up to workers shards at a time,
+// calling callback with results of every shard. Callback is called
+// concurrently for different shards. A shard stops at its first error,
+// while the others continue, and failures are returned as *MultiError
+// of *ShardError, so partial failures of a large backfill are
+// actionable.
+func RunShards[Input, Result any](
+	shards []Shard[Input, Result],
+	workers int,
+	callback func(shard string, result Result) error,
+) error {
+	return runShards(shards, workers, (*Cursor[Input, Result]).Get, callback)
+}
+
+// RunShardsAdaptive works like [RunShards], but the number of fetches
+// in flight across shards follows the limit of the controller, which
+// observes every fetch. Up to the maximum limit of the controller
+// shards run at a time.
+func RunShardsAdaptive[Input, Result any](
+	shards []Shard[Input, Result],
+	controller *AIMD,
+	callback func(shard string, result Result) error,
+) error {
+	fetches := newGate(controller.Limit)
+	return runShards(shards, int(controller.max), func(cursor *Cursor[Input, Result]) (Result, error) {
+		fetches.acquire()
+		defer fetches.release()
+
+		start := controller.clock.Now()
+		result, err := cursor.Get()
+		if !errors.Is(err, ErrStop) {
+			controller.Observe(controller.clock.Now().Sub(start), err)
+		}
+		return result, err
+	}, callback)
+}
+
+func runShards[Input, Result any](
+	shards []Shard[Input, Result],
+	workers int,
+	get func(cursor *Cursor[Input, Result]) (Result, error),
+	callback func(shard string, result Result) error,
+) error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	var (
+		wg      sync.WaitGroup
+		indexes = make(chan int)
+		errs    = make([]error, len(shards))
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = runShard(shards[i], get, callback)
+			}
+		}()
+	}
+	for i := range shards {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	var failures []error
+	for _, err := range errs {
+		if err != nil {
+			failures = append(failures, err)
+		}
+	}
+	if len(failures) > 0 {
+		return &MultiError{Errors: failures}
+	}
+	return nil
+}
+
+func runShard[Input, Result any](
+	shard Shard[Input, Result],
+	get func(cursor *Cursor[Input, Result]) (Result, error),
+	callback func(shard string, result Result) error,
+) error {
+	cursor := shard.Cursor
+	for cursor.Next() {
+		result, err := get(cursor)
+		if errors.Is(err, ErrStop) {
+			return nil
+		}
+		input := cursor.Input()
+		if err == nil {
+			input = cursor.last
+			err = callback(shard.Name, result)
+		}
+		if errors.Is(err, ErrStop) {
+			return nil
+		}
+		if err != nil {
+			return &ShardError{Shard: shard.Name, Input: cursor.describe(input), Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package iter_test
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.teddydd.me/iter"
+)
+
+func TestRunShards(t *testing.T) {
+	failure := errors.New("failure")
+
+	shards := make([]iter.Shard[int, []int], 4)
+	for i := range shards {
+		config := pagesConfig([][]int{{i * 10}, {i*10 + 1}, {i*10 + 2}})
+		if i == 1 || i == 3 {
+			fetchNext := config.FetchNext
+			config.FetchNext = func(input int) ([]int, error) {
+				if input == 2 {
+					return nil, failure
+				}
+				return fetchNext(input)
+			}
+		}
+		shards[i] = iter.Shard[int, []int]{Name: string(rune('a' + i)), Cursor: iter.New(config)}
+	}
+
+	var (
+		mu     sync.Mutex
+		values []int
+	)
+	err := iter.RunShards(shards, 2, func(shard string, page []int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		values = append(values, page...)
+		return nil
+	})
+
+	var multi *iter.MultiError
+	if !errors.As(err, &multi) || len(multi.Errors) != 2 {
+		t.Fatalf("expected two shard failures, got %v", err)
+	}
+	var shardErr *iter.ShardError
+	if !errors.As(multi.Errors[1], &shardErr) || shardErr.Shard != "d" || shardErr.Input != 2 {
+		t.Errorf("unexpected shard error: %v", multi.Errors[1])
+	}
+	if !errors.Is(err, failure) {
+		t.Errorf("expected failure to be wrapped: %v", err)
+	}
+
+	sort.Ints(values)
+	expected := []int{0, 1, 2, 10, 11, 20, 21, 22, 30, 31}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("unexpected values: got %v, want %v", values, expected)
+	}
+}
+
+func TestRunShardsRedact(t *testing.T) {
+	failure := errors.New("failure")
+	config := iter.Config[string, []int]{
+		HasNext: func(result []int) (string, bool) {
+			return "", false
+		},
+		FetchNext: func(input string) ([]int, error) {
+			return nil, failure
+		},
+		GetFirstInput: func() string {
+			return "https://api.example.com/items?sig=secret"
+		},
+		Redact: iter.RedactQuery("sig"),
+	}
+
+	err := iter.RunShards([]iter.Shard[string, []int]{{Name: "a", Cursor: iter.New(config)}}, 1, func(string, []int) error {
+		return nil
+	})
+	if err == nil || strings.Contains(err.Error(), "secret") || !strings.Contains(err.Error(), "sig=REDACTED") {
+		t.Errorf("expected redacted error, got %v", err)
+	}
+}
+
+func TestRunShardsAdaptive(t *testing.T) {
+	var running, peak atomic.Int32
+	shards := make([]iter.Shard[int, []int], 8)
+	for i := range shards {
+		config := pagesConfig([][]int{{i * 10}, {i*10 + 1}, {i*10 + 2}})
+		fetchNext := config.FetchNext
+		config.FetchNext = func(input int) ([]int, error) {
+			current := running.Add(1)
+			defer running.Add(-1)
+			for {
+				p := peak.Load()
+				if current <= p || peak.CompareAndSwap(p, current) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			return fetchNext(input)
+		}
+		shards[i] = iter.Shard[int, []int]{Name: string(rune('a' + i)), Cursor: iter.New(config)}
+	}
+
+	controller := iter.NewAIMD(nil, 1, 3, 0)
+	var pages atomic.Int32
+	err := iter.RunShardsAdaptive(shards, controller, func(shard string, page []int) error {
+		pages.Add(1)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := pages.Load(); n != 24 {
+		t.Errorf("expected 24 pages, got %d", n)
+	}
+	if p := peak.Load(); p > 3 {
+		t.Errorf("expected at most 3 fetches in flight, got %d", p)
+	}
+	if limit := controller.Limit(); limit != 3 {
+		t.Errorf("expected limit to grow to 3, got %d", limit)
+	}
+}
@@ -0,0 +1,86 @@
+package iter
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// ErrInterrupted is returned by IterateUntil when the iteration was
+// stopped by shutdown.
+var ErrInterrupted = errors.New("iteration interrupted")
+
+// IterateUntil works like [Cursor.Iterate] but stops once stop channel
+// is closed. The page being fetched or processed is finished first, then
+// checkpoint is called (if not nil) and ErrInterrupted is returned along
+// with any error returned by checkpoint.
+func (d *Cursor[Input, Result]) IterateUntil(
+	stop <-chan struct{},
+	callback func(response Result) error,
+	checkpoint func() error,
+) error {
+	interrupted := func() error {
+		if checkpoint == nil {
+			return ErrInterrupted
+		}
+		return errors.Join(ErrInterrupted, checkpoint())
+	}
+
+	for d.Next() {
+		select {
+		case <-stop:
+			return interrupted()
+		default:
+		}
+
+		response, err := d.Get()
+		if err != nil {
+			if errors.Is(err, ErrStop) {
+				return nil
+			}
+			return err
+		}
+
+		if err := callback(response); err != nil {
+			if errors.Is(err, ErrStop) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NotifyShutdown returns a channel that is closed when one of the
+// signals is received, SIGINT or SIGTERM by default. It is meant to be
+// passed to [Cursor.IterateUntil]. Call release to stop relaying
+// signals.
+func NotifyShutdown(signals ...os.Signal) (stop <-chan struct{}, release func()) {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	received := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	quit := make(chan struct{})
+	signal.Notify(received, signals...)
+
+	go func() {
+		select {
+		case <-received:
+			close(done)
+		case <-quit:
+		}
+	}()
+
+	var once sync.Once
+	return done, func() {
+		once.Do(func() {
+			signal.Stop(received)
+			close(quit)
+		})
+	}
+}
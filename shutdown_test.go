@@ -0,0 +1,107 @@
+package iter_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"go.teddydd.me/iter"
+)
+
+func TestIterateUntil(t *testing.T) {
+	mockServer := httptest.NewServer(MockAPIHandler(5))
+	defer mockServer.Close()
+	iterator := simpleIterator(mockServer)
+
+	t.Run("interrupted", func(t *testing.T) {
+		iterator.Reset()
+		stop := make(chan struct{})
+		checkpoints := 0
+
+		var results []Record
+		err := iterator.IterateUntil(stop, func(response []Record) error {
+			results = append(results, response...)
+			close(stop)
+			return nil
+		}, func() error {
+			checkpoints++
+			return nil
+		})
+		if !errors.Is(err, iter.ErrInterrupted) {
+			t.Fatalf("expected ErrInterrupted, got %+v", err)
+		}
+		if checkpoints != 1 {
+			t.Errorf("expected checkpoint to be called once, got %d", checkpoints)
+		}
+		if !reflect.DeepEqual(results, []Record{{1}, {2}}) {
+			t.Errorf("expected only first page to be processed, got %+v", results)
+		}
+	})
+
+	t.Run("checkpoint error", func(t *testing.T) {
+		iterator.Reset()
+		stop := make(chan struct{})
+		close(stop)
+		checkpointErr := errors.New("checkpoint failed")
+
+		err := iterator.IterateUntil(stop, func(response []Record) error {
+			t.Fatalf("should not be called")
+			return nil
+		}, func() error {
+			return checkpointErr
+		})
+		if !errors.Is(err, iter.ErrInterrupted) || !errors.Is(err, checkpointErr) {
+			t.Fatalf("expected ErrInterrupted and checkpoint error, got %+v", err)
+		}
+	})
+
+	t.Run("not interrupted", func(t *testing.T) {
+		iterator.Reset()
+		stop, release := iter.NotifyShutdown()
+		defer release()
+
+		var results []Record
+		err := iterator.IterateUntil(stop, func(response []Record) error {
+			results = append(results, response...)
+			return nil
+		}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if len(results) != 5 {
+			t.Errorf("expected all records, got %+v", results)
+		}
+	})
+}
+
+func TestNotifyShutdownRelease(t *testing.T) {
+	// os/signal starts its watcher goroutine on first use
+	_, release := iter.NotifyShutdown()
+	release()
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 10; i++ {
+		_, release := iter.NotifyShutdown()
+		release()
+		release()
+	}
+
+	checkGoroutines(t, before)
+}
+
+// checkGoroutines fails the test if goroutines started since there were
+// before goroutines do not exit within a second.
+func checkGoroutines(t *testing.T, before int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutines leaked: %d before, %d after", before, runtime.NumGoroutine())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,110 @@
+package iter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrFetchPanicked is returned to callers of [FlightGroup] waiting for
+// a fetch that panicked.
+var ErrFetchPanicked = errors.New("shared fetch panicked")
+
+// FlightGroup coalesces identical fetches of several cursors running
+// concurrently, e.g. shards or consumers of the same feed, so the
+// backend sees one request and all callers share its result. Results
+// are shared, so callers must not modify them. Only the fetch of one
+// cursor runs, so HasNext must derive the next input from the Result
+// alone, not from side effects of FetchNext. It is safe for concurrent
+// use.
+type FlightGroup[Input, Result any] struct {
+	key   func(input Input) string
+	mu    sync.Mutex
+	calls map[string]*flight[Result]
+}
+
+type flight[Result any] struct {
+	done   chan struct{}
+	result Result
+	err    error
+}
+
+// NewFlightGroup creates a group identifying fetches by key of their
+// inputs; nil key formats inputs with fmt.
+func NewFlightGroup[Input, Result any](key func(input Input) string) *FlightGroup[Input, Result] {
+	if key == nil {
+		key = func(input Input) string {
+			return fmt.Sprintf("%#v", input)
+		}
+	}
+	return &FlightGroup[Input, Result]{key: key, calls: make(map[string]*flight[Result])}
+}
+
+// Middleware returns middleware joining fetches of the cursor to
+// identical fetches in flight in the group. Fetches of the first page
+// are only joined with fetches of the first page, as FetchFirst may
+// differ from FetchNext for the same input. Waiting for a fetch of
+// another cursor stops once the context of the fetch is done.
+func (g *FlightGroup[Input, Result]) Middleware() Middleware[Input, Result] {
+	return func(config Config[Input, Result]) Config[Input, Result] {
+		config.wrapFetchContext(g.wrap("next"), g.wrap("first"))
+		return config
+	}
+}
+
+func (g *FlightGroup[Input, Result]) wrap(
+	phase string,
+) func(fetch func(ctx context.Context, input Input) (Result, error)) func(ctx context.Context, input Input) (Result, error) {
+	return func(fetch func(ctx context.Context, input Input) (Result, error)) func(ctx context.Context, input Input) (Result, error) {
+		return func(ctx context.Context, input Input) (Result, error) {
+			return g.do(ctx, phase+"\x00"+g.key(input), input, fetch)
+		}
+	}
+}
+
+func (g *FlightGroup[Input, Result]) do(
+	ctx context.Context,
+	key string,
+	input Input,
+	fetch func(ctx context.Context, input Input) (Result, error),
+) (Result, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.result, call.err
+		case <-ctx.Done():
+			var zero Result
+			return zero, ctx.Err()
+		}
+	}
+	call := &flight[Result]{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	returned := false
+	defer func() {
+		var recovered any
+		if !returned {
+			// the panic is passed on to the caller, waiters get an
+			// error instead of a zero Result
+			recovered = recover()
+			call.err = fmt.Errorf("%w: %v", ErrFetchPanicked, recovered)
+		}
+
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+
+		if recovered != nil {
+			panic(recovered)
+		}
+	}()
+
+	call.result, call.err = fetch(ctx, input)
+	returned = true
+	return call.result, call.err
+}
@@ -0,0 +1,164 @@
+package iter_test
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.teddydd.me/iter"
+)
+
+func TestFlightGroup(t *testing.T) {
+	var fetches atomic.Int64
+	release := make(chan struct{})
+	group := iter.NewFlightGroup[int, []int](nil)
+
+	newCursor := func() *iter.Cursor[int, []int] {
+		config := iter.Config[int, []int]{
+			HasNext: func(result []int) (int, bool) {
+				return 0, false
+			},
+			FetchNext: func(input int) ([]int, error) {
+				fetches.Add(1)
+				<-release
+				return []int{1, 2}, nil
+			},
+			GetFirstInput: func() int {
+				return 0
+			},
+		}
+		return iter.New(iter.Use(config, group.Middleware()))
+	}
+
+	const consumers = 4
+	results := make([][][]int, consumers)
+	var wg sync.WaitGroup
+	for i := 0; i < consumers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = newCursor().Collect()
+		}(i)
+	}
+
+	// let all consumers join the fetch in flight
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("expected one fetch, got %d", n)
+	}
+	for i, result := range results {
+		if !reflect.DeepEqual(result, [][]int{{1, 2}}) {
+			t.Errorf("unexpected result of consumer %d: %v", i, result)
+		}
+	}
+
+	if _, err := newCursor().Collect(); err != nil || fetches.Load() != 2 {
+		t.Errorf("expected new fetch after completion, got %d: %v", fetches.Load(), err)
+	}
+}
+
+func TestFlightGroupFirstPage(t *testing.T) {
+	var first, next atomic.Int64
+	release := make(chan struct{})
+	group := iter.NewFlightGroup[int, []int](nil)
+
+	config := iter.Config[int, []int]{
+		HasNext: func(result []int) (int, bool) {
+			return 0, false
+		},
+		FetchFirst: func(input int) ([]int, error) {
+			first.Add(1)
+			<-release
+			return []int{1}, nil
+		},
+		FetchNext: func(input int) ([]int, error) {
+			next.Add(1)
+			<-release
+			return []int{2}, nil
+		},
+		GetFirstInput: func() int {
+			return 0
+		},
+	}
+	fresh := iter.New(iter.Use(config, group.Middleware()))
+	resumed := iter.New(iter.Use(config, group.Middleware()))
+	resumed.Restore(iter.Checkpoint[int]{Input: 0})
+
+	var wg sync.WaitGroup
+	var firstPage, nextPage []int
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		firstPage, _ = fresh.Get()
+	}()
+	go func() {
+		defer wg.Done()
+		nextPage, _ = resumed.Get()
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if first.Load() != 1 || next.Load() != 1 {
+		t.Errorf("expected separate fetches, got %d first and %d next", first.Load(), next.Load())
+	}
+	if !reflect.DeepEqual(firstPage, []int{1}) || !reflect.DeepEqual(nextPage, []int{2}) {
+		t.Errorf("unexpected pages: %v, %v", firstPage, nextPage)
+	}
+}
+
+func TestFlightGroupPanic(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	group := iter.NewFlightGroup[int, []int](nil)
+
+	newCursor := func(fetch func(input int) ([]int, error)) *iter.Cursor[int, []int] {
+		return iter.New(iter.Use(iter.Config[int, []int]{
+			HasNext: func(result []int) (int, bool) {
+				return 0, false
+			},
+			FetchNext: fetch,
+			GetFirstInput: func() int {
+				return 0
+			},
+		}, group.Middleware()))
+	}
+
+	leader := newCursor(func(input int) ([]int, error) {
+		close(started)
+		<-release
+		panic("boom")
+	})
+	waiter := newCursor(func(input int) ([]int, error) {
+		t.Error("waiter fetched")
+		return nil, nil
+	})
+
+	panicked := make(chan any)
+	go func() {
+		defer func() { panicked <- recover() }()
+		leader.Get()
+	}()
+	<-started
+
+	errs := make(chan error)
+	go func() {
+		_, err := waiter.Get()
+		errs <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	if r := <-panicked; r != "boom" {
+		t.Errorf("expected the panic to reach the leader, got %v", r)
+	}
+	if err := <-errs; !errors.Is(err, iter.ErrFetchPanicked) {
+		t.Errorf("expected ErrFetchPanicked, got %v", err)
+	}
+}
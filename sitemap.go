@@ -0,0 +1,104 @@
+package iter
+
+import (
+	"compress/gzip"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// SitemapURL is an entry of a sitemap.
+type SitemapURL struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod"`
+	ChangeFreq string `xml:"changefreq"`
+	Priority   string `xml:"priority"`
+}
+
+type sitemapDocument struct {
+	URLs     []SitemapURL `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+// NewSitemap creates a cursor over URLs listed in a sitemap. If the
+// document is a sitemap index, its child sitemaps are fetched lazily,
+// one per page, and the index of the next child sitemap is used as
+// input. Gzipped sitemaps are decompressed.
+func NewSitemap(h HTTP, url string) *Cursor[int, Page[int, SitemapURL]] {
+	var children []string
+
+	fetch := func(url string) (sitemapDocument, error) {
+		var doc sitemapDocument
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return doc, h.redactError(err)
+		}
+		resp, err := h.do(req)
+		if err != nil {
+			return doc, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return doc, &StatusError{StatusCode: resp.StatusCode, URL: h.redact(url)}
+		}
+
+		var body io.Reader = resp.Body
+		if strings.HasSuffix(req.URL.Path, ".gz") {
+			gz, err := gzip.NewReader(resp.Body)
+			if err != nil {
+				return doc, err
+			}
+			defer gz.Close()
+			body = gz
+		}
+
+		err = xml.NewDecoder(body).Decode(&doc)
+		return doc, err
+	}
+
+	return New(Config[int, Page[int, SitemapURL]]{
+		HasNext: func(result Page[int, SitemapURL]) (int, bool) {
+			return result.Next, result.Next < len(children)
+		},
+		FetchNext: func(input int) (Page[int, SitemapURL], error) {
+			if children == nil {
+				doc, err := fetch(url)
+				if err != nil {
+					return Page[int, SitemapURL]{Next: input}, err
+				}
+
+				if len(doc.Sitemaps) == 0 {
+					// not an index, the only page is the sitemap itself
+					if input > 0 {
+						return Page[int, SitemapURL]{Next: input}, nil
+					}
+					return Page[int, SitemapURL]{Items: doc.URLs, Next: input + 1}, nil
+				}
+
+				children = make([]string, len(doc.Sitemaps))
+				for i, sitemap := range doc.Sitemaps {
+					children[i] = strings.TrimSpace(sitemap.Loc)
+				}
+			}
+
+			if input >= len(children) {
+				return Page[int, SitemapURL]{Next: input}, nil
+			}
+
+			doc, err := fetch(children[input])
+			if err != nil {
+				return Page[int, SitemapURL]{Next: input}, err
+			}
+			return Page[int, SitemapURL]{Items: doc.URLs, Next: input + 1}, nil
+		},
+		GetFirstInput: func() int {
+			children = nil
+			return 0
+		},
+	})
+}
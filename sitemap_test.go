@@ -0,0 +1,89 @@
+package iter_test
+
+import (
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func sitemapHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>http://%[1]s/pages.xml</loc></sitemap>
+  <sitemap><loc>http://%[1]s/posts.xml.gz</loc></sitemap>
+</sitemapindex>`, r.Host)
+	})
+	mux.HandleFunc("/pages.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>http://example.com/</loc><priority>1.0</priority></url>
+  <url><loc>http://example.com/about</loc></url>
+</urlset>`)
+	})
+	mux.HandleFunc("/posts.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		fmt.Fprint(gz, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>http://example.com/posts/1</loc><lastmod>2023-01-01</lastmod></url>
+</urlset>`)
+	})
+	return mux
+}
+
+func sitemapLocs(t *testing.T, iterator *iter.Cursor[int, iter.Page[int, iter.SitemapURL]]) [][]string {
+	t.Helper()
+
+	var pages [][]string
+	err := iterator.Iterate(func(response iter.Page[int, iter.SitemapURL]) error {
+		var locs []string
+		for _, url := range response.Items {
+			locs = append(locs, url.Loc)
+		}
+		pages = append(pages, locs)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return pages
+}
+
+func TestNewSitemap(t *testing.T) {
+	mockServer := httptest.NewServer(sitemapHandler())
+	defer mockServer.Close()
+
+	t.Run("index", func(t *testing.T) {
+		pages := sitemapLocs(t, iter.NewSitemap(iter.HTTP{}, mockServer.URL+"/sitemap.xml"))
+		expected := [][]string{
+			{"http://example.com/", "http://example.com/about"},
+			{"http://example.com/posts/1"},
+		}
+		if !reflect.DeepEqual(pages, expected) {
+			t.Errorf("unexpected pages: %+v", pages)
+		}
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		iterator := iter.NewSitemap(iter.HTTP{}, mockServer.URL+"/sitemap.xml")
+		iterator.Restore(iter.Checkpoint[int]{Input: 1})
+		pages := sitemapLocs(t, iterator)
+		if !reflect.DeepEqual(pages, [][]string{{"http://example.com/posts/1"}}) {
+			t.Errorf("unexpected pages: %+v", pages)
+		}
+	})
+
+	t.Run("plain sitemap", func(t *testing.T) {
+		pages := sitemapLocs(t, iter.NewSitemap(iter.HTTP{}, mockServer.URL+"/pages.xml"))
+		if !reflect.DeepEqual(pages, [][]string{{"http://example.com/", "http://example.com/about"}}) {
+			t.Errorf("unexpected pages: %+v", pages)
+		}
+	})
+}
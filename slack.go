@@ -0,0 +1,139 @@
+package iter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// SlackConfig configures iteration over Slack Web API methods using
+// cursor-based pagination.
+type SlackConfig struct {
+	HTTP
+	// URL of the API method, e.g. https://slack.com/api/users.list
+	URL string
+	// Token used to authenticate requests.
+	Token string
+	// Params are additional query parameters, e.g. channel or limit.
+	Params url.Values
+	// Field is the name of the response field holding the items, e.g.
+	// "members" or "messages".
+	Field string
+	// MaxRetries limits retries of rate limited requests, 5 if zero.
+	MaxRetries int
+}
+
+// SlackError is returned when Slack responds with "ok": false.
+type SlackError struct {
+	Code string
+}
+
+func (e *SlackError) Error() string {
+	return fmt.Sprintf("slack: %s", e.Code)
+}
+
+// NewSlack creates a cursor over a Slack Web API method following
+// response_metadata.next_cursor. Rate limited requests are retried after
+// the delay requested by Slack, so methods of every rate limit tier can
+// be drained with a single cursor, up to MaxRetries times per request.
+func NewSlack[Item any](config SlackConfig) *Cursor[string, Page[string, Item]] {
+	maxRetries := config.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = 5
+	}
+
+	fetch := func(ctx context.Context, cursor string) (*http.Response, error) {
+		params := url.Values{}
+		for key, values := range config.Params {
+			params[key] = values
+		}
+		if cursor != "" {
+			params.Set("cursor", cursor)
+		}
+
+		rawURL, err := SetQuery(config.URL, params)
+		if err != nil {
+			return nil, config.redactError(err)
+		}
+
+		for attempt := 0; ; attempt++ {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+			if err != nil {
+				return nil, config.redactError(err)
+			}
+			req.Header.Set("Authorization", "Bearer "+config.Token)
+
+			resp, err := config.do(req)
+			if err != nil {
+				return nil, err
+			}
+
+			delay, ok := retryAfter(resp.Header)
+			if resp.StatusCode != http.StatusTooManyRequests || !ok {
+				return resp, nil
+			}
+			resp.Body.Close()
+
+			if attempt >= maxRetries {
+				return nil, &StatusError{StatusCode: resp.StatusCode, URL: config.redact(rawURL)}
+			}
+			config.clock().Sleep(delay)
+		}
+	}
+
+	return New(Config[string, Page[string, Item]]{
+		HasNext: func(result Page[string, Item]) (string, bool) {
+			return result.Next, result.Next != ""
+		},
+		FetchNextContext: func(ctx context.Context, input string) (Page[string, Item], error) {
+			resp, err := fetch(ctx, input)
+			if err != nil {
+				return Page[string, Item]{}, err
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				return Page[string, Item]{}, &StatusError{StatusCode: resp.StatusCode, URL: config.redact(config.URL)}
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return Page[string, Item]{}, err
+			}
+
+			var response struct {
+				OK       bool   `json:"ok"`
+				Error    string `json:"error"`
+				Metadata struct {
+					NextCursor string `json:"next_cursor"`
+				} `json:"response_metadata"`
+			}
+			if err := json.Unmarshal(body, &response); err != nil {
+				return Page[string, Item]{}, err
+			}
+			if !response.OK {
+				return Page[string, Item]{}, &SlackError{Code: response.Error}
+			}
+
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(body, &fields); err != nil {
+				return Page[string, Item]{}, err
+			}
+
+			var items []Item
+			if raw, ok := fields[config.Field]; ok {
+				if err := json.Unmarshal(raw, &items); err != nil {
+					return Page[string, Item]{}, err
+				}
+			}
+
+			return Page[string, Item]{Items: items, Next: response.Metadata.NextCursor}, nil
+		},
+		GetFirstInput: func() string {
+			return ""
+		},
+	})
+}
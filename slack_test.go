@@ -0,0 +1,107 @@
+package iter_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+type member struct {
+	ID string `json:"id"`
+}
+
+func TestNewSlack(t *testing.T) {
+	limited := true
+	pages := map[string]struct {
+		members []member
+		next    string
+	}{
+		"":             {members: []member{{"U1"}, {"U2"}}, next: "dXNlcjpVMw=="},
+		"dXNlcjpVMw==": {members: []member{{"U3"}}, next: ""},
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer xoxb-token" {
+			json.NewEncoder(w).Encode(map[string]any{"ok": false, "error": "not_authed"})
+			return
+		}
+		if r.URL.Query().Get("limit") != "2" {
+			t.Errorf("expected limit parameter, got %q", r.URL.RawQuery)
+		}
+		if limited {
+			limited = false
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		page := pages[r.URL.Query().Get("cursor")]
+		json.NewEncoder(w).Encode(map[string]any{
+			"ok":                true,
+			"members":           page.members,
+			"response_metadata": map[string]string{"next_cursor": page.next},
+		})
+	}))
+	defer mockServer.Close()
+
+	config := iter.SlackConfig{
+		URL:    mockServer.URL + "/users.list",
+		Token:  "xoxb-token",
+		Params: url.Values{"limit": {"2"}},
+		Field:  "members",
+	}
+
+	var members []member
+	err := iter.NewSlack[member](config).Iterate(func(response iter.Page[string, member]) error {
+		members = append(members, response.Items...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(members, []member{{"U1"}, {"U2"}, {"U3"}}) {
+		t.Errorf("unexpected members: %+v", members)
+	}
+
+	t.Run("slack error", func(t *testing.T) {
+		config := config
+		config.Token = "wrong"
+		_, err := iter.NewSlack[member](config).Get()
+
+		var slackErr *iter.SlackError
+		if !errors.As(err, &slackErr) || slackErr.Code != "not_authed" {
+			t.Fatalf("expected slack error, got %+v", err)
+		}
+	})
+	t.Run("rate limit retries", func(t *testing.T) {
+		var requests int
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.URL.Query().Get("team") != "T1" || r.URL.Query().Get("limit") != "2" {
+				t.Errorf("unexpected query: %q", r.URL.RawQuery)
+			}
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer mockServer.Close()
+
+		config := config
+		config.URL = mockServer.URL + "/users.list?team=T1"
+		config.MaxRetries = 2
+		_, err := iter.NewSlack[member](config).Get()
+
+		var statusErr *iter.StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+			t.Fatalf("expected rate limit error, got %v", err)
+		}
+		if requests != 3 {
+			t.Errorf("expected 3 requests, got %d", requests)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package iter
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrSnapshotDiverged is returned by [VerifySnapshot] when the stream
+// differs from the snapshot.
+var ErrSnapshotDiverged = errors.New("stream diverged from snapshot")
+
+// DivergenceError reports where a stream diverged from a snapshot.
+type DivergenceError struct {
+	// Index is the zero based index of the first differing value.
+	Index int
+	// Expected is the JSON encoded value from the snapshot, empty if
+	// the stream is longer than the snapshot.
+	Expected string
+	// Actual is the JSON encoded value from the stream, empty if the
+	// stream is shorter than the snapshot.
+	Actual string
+}
+
+func (e *DivergenceError) Error() string {
+	return fmt.Sprintf("%v at value %d: expected %s, got %s", ErrSnapshotDiverged, e.Index, e.Expected, e.Actual)
+}
+
+// Unwrap returns ErrSnapshotDiverged.
+func (e *DivergenceError) Unwrap() error {
+	return ErrSnapshotDiverged
+}
+
+// WriteSnapshot writes values of the stream as JSON lines to a file in
+// dir named after the SHA-256 hash of its content, and returns its
+// path. Snapshots of identical iterations have the same name.
+func WriteSnapshot[T any](stream *Stream[T], dir string) (string, error) {
+	file, err := os.CreateTemp(dir, "snapshot-*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	hash := sha256.New()
+	writer := bufio.NewWriter(io.MultiWriter(file, hash))
+	err = stream.Iterate(JSONLines[T](writer).Write)
+	if err = errors.Join(err, writer.Flush(), file.Close()); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, hex.EncodeToString(hash.Sum(nil))+".jsonl")
+	return path, os.Rename(file.Name(), path)
+}
+
+// VerifySnapshot checks that values of the stream match the snapshot
+// written by [WriteSnapshot]. The first difference is reported as
+// [DivergenceError]. Offset is the number of snapshot values to skip,
+// so verification can continue with a cursor restored from a
+// checkpoint. The content of the snapshot is checked against its name.
+func VerifySnapshot[T any](stream *Stream[T], path string, offset int) error {
+	if err := checkSnapshotHash(path); err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	readLine := func() (string, error) {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF && len(line) == 0 {
+			return "", err
+		}
+		return string(bytes.TrimSuffix(line, []byte("\n"))), nil
+	}
+
+	for i := 0; i < offset; i++ {
+		if _, err := readLine(); err != nil {
+			return fmt.Errorf("snapshot has only %d values: %w", i, err)
+		}
+	}
+
+	index := offset
+	err = stream.Iterate(func(value T) error {
+		actual, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		expected, err := readLine()
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if expected != string(actual) {
+			return &DivergenceError{Index: index, Expected: expected, Actual: string(actual)}
+		}
+		index++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if expected, err := readLine(); err != io.EOF {
+		return &DivergenceError{Index: index, Expected: expected}
+	}
+	return nil
+}
+
+func checkSnapshotHash(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return err
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), ".jsonl")
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != name {
+		return fmt.Errorf("snapshot %s is corrupted: content hash is %s", path, sum)
+	}
+	return nil
+}
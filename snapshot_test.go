@@ -0,0 +1,78 @@
+package iter_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestSnapshot(t *testing.T) {
+	dir := t.TempDir()
+
+	path, err := iter.WriteSnapshot(customers(), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again, err := iter.WriteSnapshot(customers(), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != again {
+		t.Errorf("expected identical snapshots to have the same name: %s != %s", path, again)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 1 {
+		t.Errorf("unexpected files: %v", files)
+	}
+
+	t.Run("match", func(t *testing.T) {
+		if err := iter.VerifySnapshot(customers(), path, 0); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		rest := iter.Items(iter.New(pagesConfig([][]customer{{{3, "eu"}, {4, "apac"}}})))
+		if err := iter.VerifySnapshot(rest, path, 2); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("diverged", func(t *testing.T) {
+		changed := iter.Items(iter.New(pagesConfig([][]customer{{{1, "eu"}, {2, "us"}}, {{3, "us"}}})))
+
+		err := iter.VerifySnapshot(changed, path, 0)
+		var divergence *iter.DivergenceError
+		if !errors.As(err, &divergence) || !errors.Is(err, iter.ErrSnapshotDiverged) {
+			t.Fatalf("expected DivergenceError, got %v", err)
+		}
+		if divergence.Index != 2 || divergence.Actual != `{"id":3,"region":"us"}` {
+			t.Errorf("unexpected divergence: %+v", divergence)
+		}
+	})
+
+	t.Run("shorter", func(t *testing.T) {
+		short := iter.Items(iter.New(pagesConfig([][]customer{{{1, "eu"}}})))
+
+		var divergence *iter.DivergenceError
+		if err := iter.VerifySnapshot(short, path, 0); !errors.As(err, &divergence) || divergence.Index != 1 {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("corrupted", func(t *testing.T) {
+		corrupted := filepath.Join(dir, "corrupted")
+		os.Mkdir(corrupted, 0o755)
+		target := filepath.Join(corrupted, filepath.Base(path))
+		if err := os.WriteFile(target, []byte("{}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		err := iter.VerifySnapshot(customers(), target, 0)
+		if err == nil || errors.Is(err, iter.ErrSnapshotDiverged) {
+			t.Errorf("expected corruption error, got %v", err)
+		}
+	})
+}
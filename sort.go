@@ -0,0 +1,196 @@
+package iter
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+)
+
+// SortExternal returns stream of values of the stream sorted by less.
+// Values are sorted in chunks of memLimit values, chunks are written to
+// temporary files as JSON lines and merged, so streams larger than
+// memory can be sorted. The sort is stable.
+//
+// The stream is consumed on the first Get. Temporary files are removed
+// once the sorted stream is depleted or reset. If sorting fails, the
+// error is returned until the stream is reset.
+func SortExternal[T any](stream *Stream[T], less func(a, b T) bool, memLimit int) *Stream[T] {
+	if memLimit < 1 {
+		memLimit = 1
+	}
+
+	var (
+		sorted bool
+		failed error
+		runs   []*Spool[T]
+		heads  *mergeHeap[T]
+	)
+
+	cleanup := func() {
+		if heads != nil {
+			for _, head := range heads.items {
+				head.values.Reset()
+			}
+		}
+		for _, run := range runs {
+			run.Close()
+		}
+		runs, heads = nil, nil
+	}
+
+	prepare := func() error {
+		var chunk []T
+		flush := func(spill bool) error {
+			sort.SliceStable(chunk, func(i, j int) bool {
+				return less(chunk[i], chunk[j])
+			})
+			run := &Spool[T]{values: chunk, count: len(chunk)}
+			if spill {
+				var err error
+				if run, err = spillRun(chunk); err != nil {
+					return err
+				}
+			}
+			runs = append(runs, run)
+			chunk = nil
+			return nil
+		}
+
+		err := stream.Iterate(func(value T) error {
+			chunk = append(chunk, value)
+			if len(chunk) < memLimit {
+				return nil
+			}
+			return flush(true)
+		})
+		if err != nil {
+			return err
+		}
+		// the last chunk stays in memory
+		if err := flush(false); err != nil {
+			return err
+		}
+
+		heads = &mergeHeap[T]{less: less}
+		for i, run := range runs {
+			values := run.Stream()
+			value, err := values.Get()
+			if errors.Is(err, ErrStop) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			heads.items = append(heads.items, mergeHead[T]{value: value, run: i, values: values})
+		}
+		heap.Init(heads)
+		return nil
+	}
+
+	return newStream(func() (T, error) {
+		var zero T
+		if failed != nil {
+			return zero, failed
+		}
+
+		if !sorted {
+			sorted = true
+			if err := prepare(); err != nil {
+				cleanup()
+				failed = err
+				return zero, err
+			}
+		}
+
+		if heads.Len() == 0 {
+			cleanup()
+			return zero, ErrStop
+		}
+
+		head := &heads.items[0]
+		value := head.value
+		next, err := head.values.Get()
+		switch {
+		case errors.Is(err, ErrStop):
+			heap.Pop(heads)
+		case err != nil:
+			cleanup()
+			failed = err
+			return zero, err
+		default:
+			head.value = next
+			heap.Fix(heads, 0)
+		}
+		return value, nil
+	}, func() {
+		cleanup()
+		sorted, failed = false, nil
+		stream.Reset()
+	})
+}
+
+// spillRun writes values to a temporary file.
+func spillRun[T any](values []T) (*Spool[T], error) {
+	file, err := os.CreateTemp("", "iter-sort-*.jsonl")
+	if err != nil {
+		return nil, err
+	}
+	run := &Spool[T]{file: file.Name(), count: len(values)}
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, value := range values {
+		if err = encoder.Encode(value); err != nil {
+			break
+		}
+	}
+	if err = errors.Join(err, writer.Flush(), file.Close()); err != nil {
+		run.Close()
+		return nil, err
+	}
+	return run, nil
+}
+
+type mergeHead[T any] struct {
+	value  T
+	run    int
+	values *Stream[T]
+}
+
+type mergeHeap[T any] struct {
+	items []mergeHead[T]
+	less  func(a, b T) bool
+}
+
+func (h *mergeHeap[T]) Len() int {
+	return len(h.items)
+}
+
+// Less keeps the sort stable by preferring values of earlier runs.
+func (h *mergeHeap[T]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if h.less(a.value, b.value) {
+		return true
+	}
+	if h.less(b.value, a.value) {
+		return false
+	}
+	return a.run < b.run
+}
+
+func (h *mergeHeap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+}
+
+func (h *mergeHeap[T]) Push(x any) {
+	h.items = append(h.items, x.(mergeHead[T]))
+}
+
+func (h *mergeHeap[T]) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
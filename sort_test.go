@@ -0,0 +1,103 @@
+package iter_test
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+type score struct {
+	Name  string `json:"name"`
+	Score int    `json:"score"`
+}
+
+func TestSortExternal(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	var pages [][]score
+	var all []score
+	for i := 0; i < 10; i++ {
+		var page []score
+		for j := 0; j < 7; j++ {
+			s := score{Name: string(rune('a'+i)) + string(rune('a'+j)), Score: random.Intn(20)}
+			page = append(page, s)
+			all = append(all, s)
+		}
+		pages = append(pages, page)
+	}
+
+	less := func(a, b score) bool {
+		return a.Score < b.Score
+	}
+	expected := append([]score(nil), all...)
+	sort.SliceStable(expected, func(i, j int) bool {
+		return less(expected[i], expected[j])
+	})
+
+	for _, memLimit := range []int{1, 8, 100} {
+		stream := iter.SortExternal(iter.Items(iter.New(pagesConfig(pages))), less, memLimit)
+		if values := collect(t, stream); !reflect.DeepEqual(values, expected) {
+			t.Errorf("memLimit %d: unexpected order: %v", memLimit, values)
+		}
+
+		stream.Reset()
+		if values := collect(t, stream); !reflect.DeepEqual(values, expected) {
+			t.Errorf("memLimit %d: unexpected order after reset: %v", memLimit, values)
+		}
+	}
+}
+
+func TestSortExternalError(t *testing.T) {
+	failure := errors.New("failure")
+	config := pagesConfig([][]int{{3, 1}, {2}})
+	config.FetchNext = func(input int) ([]int, error) {
+		return nil, failure
+	}
+
+	stream := iter.SortExternal(iter.Items(iter.New(config)), func(a, b int) bool {
+		return a < b
+	}, 1)
+	for i := 0; i < 2; i++ {
+		if _, err := stream.Get(); !errors.Is(err, failure) {
+			t.Errorf("expected failure, got %v", err)
+		}
+	}
+}
+
+func TestSortExternalReleasesFiles(t *testing.T) {
+	fds, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("open files can not be listed")
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+
+	config := pagesConfig([][]int{{9, 8, 7, 6}, {5, 4, 3, 2, 1}})
+	stream := iter.SortExternal(iter.Items(iter.New(config)), func(a, b int) bool {
+		return a < b
+	}, 2)
+	for i := 0; i < 3; i++ {
+		if _, err := stream.Get(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stream.Reset()
+
+	after, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after) > len(fds) {
+		t.Errorf("%d files left open", len(after)-len(fds))
+	}
+	spills, err := os.ReadDir(os.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spills) > 0 {
+		t.Errorf("%d temporary files left", len(spills))
+	}
+}
@@ -0,0 +1,133 @@
+package iter
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+)
+
+// Spool holds values collected by [CollectSpill], in memory or spilled
+// to a temporary file.
+type Spool[T any] struct {
+	values []T
+	file   string
+	count  int
+}
+
+// CollectSpill collects values of the stream like Collect, but once
+// more than threshold values are collected, they are written to a
+// temporary file in dir as JSON lines, so huge streams do not have to
+// fit in memory. If dir is empty, the default directory for temporary
+// files is used. If the stream fails, values collected before the
+// failure are returned along with the error.
+//
+// The Spool must be closed to remove the temporary file.
+func CollectSpill[T any](stream *Stream[T], threshold int, dir string) (*Spool[T], error) {
+	spool := &Spool[T]{}
+
+	var (
+		file    *os.File
+		writer  *bufio.Writer
+		encoder *json.Encoder
+	)
+
+	err := stream.Iterate(func(value T) error {
+		spool.count++
+		if encoder != nil {
+			return encoder.Encode(value)
+		}
+
+		spool.values = append(spool.values, value)
+		if len(spool.values) <= threshold {
+			return nil
+		}
+
+		var err error
+		file, err = os.CreateTemp(dir, "iter-spill-*.jsonl")
+		if err != nil {
+			return err
+		}
+		spool.file = file.Name()
+		writer = bufio.NewWriter(file)
+		encoder = json.NewEncoder(writer)
+
+		for _, value := range spool.values {
+			if err := encoder.Encode(value); err != nil {
+				return err
+			}
+		}
+		spool.values = nil
+		return nil
+	})
+
+	if file != nil {
+		err = errors.Join(err, writer.Flush(), file.Close())
+	}
+	return spool, err
+}
+
+// Len returns the number of values in the spool.
+func (s *Spool[T]) Len() int {
+	return s.count
+}
+
+// Spilled reports whether values were written to a temporary file.
+func (s *Spool[T]) Spilled() bool {
+	return s.file != ""
+}
+
+// Stream returns stream of values in the spool, in collected order.
+func (s *Spool[T]) Stream() *Stream[T] {
+	if s.file == "" {
+		var i int
+		return newStream(func() (T, error) {
+			if i >= len(s.values) {
+				var zero T
+				return zero, ErrStop
+			}
+			i++
+			return s.values[i-1], nil
+		}, func() {
+			i = 0
+		})
+	}
+
+	var (
+		file    *os.File
+		decoder *json.Decoder
+	)
+
+	return newStream(func() (T, error) {
+		var value T
+		if file == nil {
+			var err error
+			if file, err = os.Open(s.file); err != nil {
+				return value, err
+			}
+			decoder = json.NewDecoder(bufio.NewReader(file))
+		}
+
+		err := decoder.Decode(&value)
+		if err == io.EOF {
+			file.Close()
+			file = nil
+			return value, ErrStop
+		}
+		return value, err
+	}, func() {
+		if file != nil {
+			file.Close()
+			file = nil
+		}
+	})
+}
+
+// Close removes the temporary file, if any.
+func (s *Spool[T]) Close() error {
+	if s.file == "" {
+		return nil
+	}
+	return os.Remove(s.file)
+}
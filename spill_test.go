@@ -0,0 +1,66 @@
+package iter_test
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestCollectSpill(t *testing.T) {
+	for _, threshold := range []int{100, 5} {
+		dir := t.TempDir()
+
+		spool, err := iter.CollectSpill(numbers(20, 3), threshold, dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if spool.Len() != 20 {
+			t.Errorf("unexpected length: %d", spool.Len())
+		}
+		if spool.Spilled() != (threshold < 20) {
+			t.Errorf("threshold %d: unexpected Spilled: %v", threshold, spool.Spilled())
+		}
+
+		expected := collect(t, numbers(20, 3))
+		stream := spool.Stream()
+		if values := collect(t, stream); !reflect.DeepEqual(values, expected) {
+			t.Errorf("threshold %d: unexpected values: %v", threshold, values)
+		}
+		stream.Reset()
+		if values := collect(t, stream); !reflect.DeepEqual(values, expected) {
+			t.Errorf("threshold %d: unexpected values after reset: %v", threshold, values)
+		}
+
+		if err := spool.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 0 {
+			t.Errorf("expected temporary files to be removed, got %v", files)
+		}
+	}
+}
+
+func TestCollectSpillPartial(t *testing.T) {
+	failure := errors.New("failure")
+	config := pagesConfig([][]int{{1, 2}, {3, 4}, {5}})
+	fetchNext := config.FetchNext
+	config.FetchNext = func(input int) ([]int, error) {
+		if input == 2 {
+			return nil, failure
+		}
+		return fetchNext(input)
+	}
+
+	spool, err := iter.CollectSpill(iter.Items(iter.New(config)), 1, t.TempDir())
+	defer spool.Close()
+	if !errors.Is(err, failure) {
+		t.Errorf("expected failure, got %v", err)
+	}
+	if values := collect(t, spool.Stream()); !reflect.DeepEqual(values, []int{1, 2, 3, 4}) {
+		t.Errorf("unexpected values: %v", values)
+	}
+}
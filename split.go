@@ -0,0 +1,39 @@
+package iter
+
+import "errors"
+
+// SplitBy returns stream of segments of consecutive values of the
+// stream. A new segment is started whenever split returns true for the
+// previous and the next value, for example on a day boundary between
+// timestamps.
+func SplitBy[T any](stream *Stream[T], split func(prev, next T) bool) *Stream[[]T] {
+	var segment []T
+
+	return newStream(func() ([]T, error) {
+		for {
+			value, err := stream.Get()
+			if errors.Is(err, ErrStop) {
+				if len(segment) == 0 {
+					return nil, ErrStop
+				}
+				result := segment
+				segment = nil
+				return result, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if len(segment) > 0 && split(segment[len(segment)-1], value) {
+				result := segment
+				segment = []T{value}
+				return result, nil
+			}
+
+			segment = append(segment, value)
+		}
+	}, func() {
+		stream.Reset()
+		segment = nil
+	})
+}
@@ -0,0 +1,29 @@
+package iter_test
+
+import (
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestSplitBy(t *testing.T) {
+	stream := iter.SplitBy(numbers(10, 3), func(prev, next int) bool {
+		// split into groups of 4
+		return prev/4 != next/4
+	})
+
+	expected := [][]int{{0, 1, 2, 3}, {4, 5, 6, 7}, {8, 9}}
+	if results := collect(t, stream); !reflect.DeepEqual(results, expected) {
+		t.Errorf("unexpected results: got %+v, want %+v", results, expected)
+	}
+
+	stream.Reset()
+	if results := collect(t, stream); !reflect.DeepEqual(results, expected) {
+		t.Errorf("unexpected results after reset: got %+v, want %+v", results, expected)
+	}
+
+	if results := collect(t, iter.SplitBy(numbers(0, 3), func(prev, next int) bool { return true })); len(results) != 0 {
+		t.Errorf("expected no segments for empty stream, got %+v", results)
+	}
+}
@@ -0,0 +1,161 @@
+package iter
+
+import (
+	"math"
+	"sort"
+)
+
+// TDigest estimates quantiles of a stream of numbers with bounded
+// memory. Higher compression is more accurate and uses more memory.
+type TDigest struct {
+	compression float64
+	centroids   []centroid
+	buffer      []centroid
+	count       float64
+	min, max    float64
+}
+
+type centroid struct {
+	mean, weight float64
+}
+
+// NewTDigest creates a t-digest, compression 100 is a reasonable
+// default.
+func NewTDigest(compression float64) *TDigest {
+	if compression < 10 {
+		compression = 10
+	}
+	return &TDigest{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// Add adds the value to the digest.
+func (d *TDigest) Add(value float64) {
+	d.buffer = append(d.buffer, centroid{mean: value, weight: 1})
+	d.count++
+	d.min = math.Min(d.min, value)
+	d.max = math.Max(d.max, value)
+	if len(d.buffer) >= int(5*d.compression) {
+		d.compress()
+	}
+}
+
+// compress merges buffered values into centroids, keeping centroids
+// near the tails small.
+func (d *TDigest) compress() {
+	if len(d.buffer) == 0 {
+		return
+	}
+
+	all := append(d.centroids, d.buffer...)
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].mean < all[j].mean
+	})
+
+	merged := []centroid{all[0]}
+	var cumulative float64
+	for _, c := range all[1:] {
+		last := &merged[len(merged)-1]
+		weight := last.weight + c.weight
+		q := (cumulative + weight/2) / d.count
+		if weight <= 4*d.count*q*(1-q)/d.compression {
+			last.mean += (c.mean - last.mean) * c.weight / weight
+			last.weight = weight
+			continue
+		}
+		cumulative += last.weight
+		merged = append(merged, c)
+	}
+
+	d.centroids, d.buffer = merged, nil
+}
+
+// Quantile returns the estimated value at quantile q in range [0, 1],
+// or NaN if the digest is empty.
+func (d *TDigest) Quantile(q float64) float64 {
+	d.compress()
+	if len(d.centroids) == 0 {
+		return math.NaN()
+	}
+	if q <= 0 {
+		return d.min
+	}
+	if q >= 1 {
+		return d.max
+	}
+
+	target := q * d.count
+	var cumulative float64
+	prevCenter, prevMean := 0.0, d.min
+	for _, c := range d.centroids {
+		center := cumulative + c.weight/2
+		if target < center {
+			return interpolate(prevCenter, prevMean, center, c.mean, target)
+		}
+		prevCenter, prevMean = center, c.mean
+		cumulative += c.weight
+	}
+	return interpolate(prevCenter, prevMean, d.count, d.max, target)
+}
+
+func interpolate(x0, y0, x1, y1, x float64) float64 {
+	if x1 == x0 {
+		return y0
+	}
+	return y0 + (y1-y0)*(x-x0)/(x1-x0)
+}
+
+// Stats are statistics of numbers computed in a single pass.
+type Stats struct {
+	Count    int
+	Min, Max float64
+	Sum      float64
+	digest   *TDigest
+}
+
+// Mean returns the arithmetic mean, or NaN if there were no values.
+func (s Stats) Mean() float64 {
+	if s.Count == 0 {
+		return math.NaN()
+	}
+	return s.Sum / float64(s.Count)
+}
+
+// Quantile returns the estimated value at quantile q in range [0, 1],
+// e.g. 0.99 for 99th percentile.
+func (s Stats) Quantile(q float64) float64 {
+	if s.digest == nil {
+		return math.NaN()
+	}
+	return s.digest.Quantile(q)
+}
+
+// Aggregate computes statistics of numbers extracted from values of the
+// stream. If the stream fails, statistics of values seen before the
+// failure are returned along with the error.
+func Aggregate[T any](stream *Stream[T], number func(value T) float64) (Stats, error) {
+	stats := Stats{
+		Min:    math.NaN(),
+		Max:    math.NaN(),
+		digest: NewTDigest(100),
+	}
+
+	err := stream.Iterate(func(value T) error {
+		n := number(value)
+		if stats.Count == 0 || n < stats.Min {
+			stats.Min = n
+		}
+		if stats.Count == 0 || n > stats.Max {
+			stats.Max = n
+		}
+		stats.Count++
+		stats.Sum += n
+		stats.digest.Add(n)
+		return nil
+	})
+
+	return stats, err
+}
@@ -0,0 +1,53 @@
+package iter_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestAggregate(t *testing.T) {
+	stats, err := iter.Aggregate(numbers(1000, 100), func(n int) float64 {
+		return float64(n + 1)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stats.Count != 1000 || stats.Min != 1 || stats.Max != 1000 || stats.Sum != 500500 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if stats.Mean() != 500.5 {
+		t.Errorf("unexpected mean: %v", stats.Mean())
+	}
+	for _, q := range []float64{0.5, 0.9, 0.99} {
+		if p := stats.Quantile(q); math.Abs(p-q*1000) > 5 {
+			t.Errorf("unexpected quantile %v: %v", q, p)
+		}
+	}
+
+	empty, _ := iter.Aggregate(numbers(0, 1), func(n int) float64 {
+		return float64(n)
+	})
+	if !math.IsNaN(empty.Mean()) || !math.IsNaN(empty.Quantile(0.5)) {
+		t.Errorf("expected NaN for empty stats")
+	}
+}
+
+func TestTDigest(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	digest := iter.NewTDigest(100)
+	for i := 0; i < 100000; i++ {
+		digest.Add(random.NormFloat64())
+	}
+
+	// quantiles of the standard normal distribution
+	expected := map[float64]float64{0.01: -2.326, 0.5: 0, 0.9: 1.2816, 0.999: 3.09}
+	for q, value := range expected {
+		if got := digest.Quantile(q); math.Abs(got-value) > 0.05 {
+			t.Errorf("quantile %v: got %v, expected %v", q, got, value)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package iter
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// CheckpointStore persists encoded checkpoints by key. Implementations
+// must be safe for concurrent use.
+type CheckpointStore interface {
+	// Load returns data saved under key, or nil if there is none.
+	Load(key string) ([]byte, error)
+	Save(key string, data []byte) error
+}
+
+// MemoryStore is a CheckpointStore keeping checkpoints in memory.
+type MemoryStore struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+// NewMemoryStore creates an empty store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: make(map[string][]byte)}
+}
+
+// Load returns data saved under key.
+func (s *MemoryStore) Load(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data[key], nil
+}
+
+// Save stores copy of data under key.
+func (s *MemoryStore) Save(key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = append([]byte(nil), data...)
+	return nil
+}
+
+// DirStore is a CheckpointStore keeping every checkpoint in a file of
+// the directory. Files are replaced atomically.
+type DirStore string
+
+// Load returns content of the file of key.
+func (d DirStore) Load(key string) ([]byte, error) {
+	data, err := os.ReadFile(d.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Save writes data to the file of key.
+func (d DirStore) Save(key string, data []byte) error {
+	file, err := os.CreateTemp(string(d), ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if err = errors.Join(err, file.Close()); err == nil {
+		err = os.Rename(file.Name(), d.path(key))
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}
+
+func (d DirStore) path(key string) string {
+	return filepath.Join(string(d), url.PathEscape(key)+".json")
+}
+
+// RunShardsCheckpointed works like [RunShards], but every shard is
+// restored from its checkpoint saved in store under key prefix/name
+// before starting, and its checkpoint is saved after every result
+// processed by callback. A crashed run then resumes all shards from
+// their own positions, and depleted shards are not iterated again.
+func RunShardsCheckpointed[Input, Result any](
+	store CheckpointStore,
+	prefix string,
+	shards []Shard[Input, Result],
+	workers int,
+	callback func(shard string, result Result) error,
+) error {
+	cursors := make(map[string]*Cursor[Input, Result], len(shards))
+	for _, shard := range shards {
+		data, err := store.Load(prefix + "/" + shard.Name)
+		if err != nil {
+			return &ShardError{Shard: shard.Name, Input: shard.Cursor.describe(shard.Cursor.Input()), Err: err}
+		}
+		if data != nil {
+			var checkpoint Checkpoint[Input]
+			if err := json.Unmarshal(data, &checkpoint); err != nil {
+				return &ShardError{Shard: shard.Name, Input: shard.Cursor.describe(shard.Cursor.Input()), Err: err}
+			}
+			shard.Cursor.Restore(checkpoint)
+		}
+		cursors[shard.Name] = shard.Cursor
+	}
+
+	return RunShards(shards, workers, func(shard string, result Result) error {
+		if err := callback(shard, result); err != nil {
+			return err
+		}
+		data, err := json.Marshal(cursors[shard].Checkpoint())
+		if err != nil {
+			return err
+		}
+		return store.Save(prefix+"/"+shard, data)
+	})
+}
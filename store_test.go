@@ -0,0 +1,83 @@
+package iter_test
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestDirStore(t *testing.T) {
+	store := iter.DirStore(t.TempDir())
+
+	if data, err := store.Load("backfill/a"); data != nil || err != nil {
+		t.Fatalf("expected no data, got %q, %v", data, err)
+	}
+	for _, data := range []string{"1", "2"} {
+		if err := store.Save("backfill/a", []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if data, err := store.Load("backfill/a"); string(data) != "2" || err != nil {
+		t.Errorf("unexpected data: %q, %v", data, err)
+	}
+}
+
+func TestRunShardsCheckpointed(t *testing.T) {
+	crash := errors.New("crash")
+	store := iter.NewMemoryStore()
+
+	shards := func(crashing bool) []iter.Shard[int, []int] {
+		shards := make([]iter.Shard[int, []int], 3)
+		for i := range shards {
+			config := pagesConfig([][]int{{i * 10}, {i*10 + 1}, {i*10 + 2}})
+			if crashing && i > 0 {
+				fetchNext := config.FetchNext
+				failAt := i
+				config.FetchNext = func(input int) ([]int, error) {
+					if input == failAt {
+						return nil, crash
+					}
+					return fetchNext(input)
+				}
+			}
+			shards[i] = iter.Shard[int, []int]{Name: string(rune('a' + i)), Cursor: iter.New(config)}
+		}
+		return shards
+	}
+
+	run := func(crashing bool) ([]int, error) {
+		var (
+			mu     sync.Mutex
+			values []int
+		)
+		err := iter.RunShardsCheckpointed(store, "backfill", shards(crashing), 3, func(shard string, page []int) error {
+			mu.Lock()
+			defer mu.Unlock()
+			values = append(values, page...)
+			return nil
+		})
+		sort.Ints(values)
+		return values, err
+	}
+
+	values, err := run(true)
+	if !errors.Is(err, crash) {
+		t.Fatalf("expected crash, got %v", err)
+	}
+	if expected := []int{0, 1, 2, 10, 20, 21}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("unexpected values of crashed run: got %v, want %v", values, expected)
+	}
+
+	// every shard resumes from its own position
+	values, err = run(false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []int{11, 12, 22}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("unexpected values of resumed run: got %v, want %v", values, expected)
+	}
+}
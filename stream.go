@@ -0,0 +1,153 @@
+package iter
+
+import "errors"
+
+// Stream iterates over values one at a time. It follows the same
+// protocol as [Cursor]: Next reports whether more values may be
+// available and Get returns ErrStop once the stream is depleted.
+type Stream[T any] struct {
+	pull  func() (T, error)
+	reset func()
+	done  bool
+	// ahead holds values pulled by Lookahead and not consumed yet.
+	ahead    []T
+	depleted bool
+}
+
+func newStream[T any](pull func() (T, error), reset func()) *Stream[T] {
+	return &Stream[T]{
+		pull:  pull,
+		reset: reset,
+	}
+}
+
+// Next returns true if there may be more values to iterate, false
+// otherwise.
+func (s *Stream[T]) Next() bool {
+	return !s.done
+}
+
+// Get returns the next value of the stream. ErrStop is returned if
+// there are no more values.
+func (s *Stream[T]) Get() (T, error) {
+	if s.done {
+		var zero T
+		return zero, ErrStop
+	}
+
+	if len(s.ahead) > 0 {
+		value := s.ahead[0]
+		s.ahead = s.ahead[1:]
+		return value, nil
+	}
+	if s.depleted {
+		s.done = true
+		var zero T
+		return zero, ErrStop
+	}
+
+	value, err := s.pull()
+	if errors.Is(err, ErrStop) {
+		s.done = true
+	}
+
+	return value, err
+}
+
+// Iterate iterates over the values using the provided callback function.
+// It stops iterating if the callback function returns the ErrStop
+// sentinel error. Any other error returned by the callback function will
+// be propagated.
+func (s *Stream[T]) Iterate(callback func(value T) error) error {
+	return iterate(s.Next, s.Get, callback)
+}
+
+// Reset restarts the stream and its underlying cursor.
+func (s *Stream[T]) Reset() {
+	s.reset()
+	s.done = false
+	s.ahead, s.depleted = nil, false
+}
+
+// Iterator is implemented by [Cursor], [Stream] and any other source
+// following the same protocol: Next reports whether more values may be
+// available and Get returns ErrStop once the source is depleted.
+type Iterator[T any] interface {
+	Next() bool
+	Get() (T, error)
+}
+
+// FromIterator returns stream of values of the iterator, so sources
+// that are not cursors can be used with all stream combinators.
+// Resetting the stream resets the iterator if it has a Reset method.
+func FromIterator[T any](iterator Iterator[T]) *Stream[T] {
+	reset := func() {}
+	if resetter, ok := iterator.(interface{ Reset() }); ok {
+		reset = resetter.Reset
+	}
+	return newStream(func() (T, error) {
+		if !iterator.Next() {
+			var zero T
+			return zero, ErrStop
+		}
+		return iterator.Get()
+	}, reset)
+}
+
+// Pages returns stream of Results fetched by the cursor.
+func Pages[Input, Result any](cursor *Cursor[Input, Result]) *Stream[Result] {
+	return FromIterator[Result](cursor)
+}
+
+// Indexed is an item along with its position in the iteration.
+type Indexed[T any] struct {
+	// Page is zero based index of the page the item was fetched in.
+	Page int
+	// Index is zero based index of the item within its page.
+	Index int
+	Item  T
+}
+
+// Enumerate returns stream of items of pages fetched by the cursor
+// along with their positions, so consumers can report errors or resume
+// tokens like "page 512, item 37".
+func Enumerate[Input, T any](cursor *Cursor[Input, []T]) *Stream[Indexed[T]] {
+	var (
+		page      []T
+		pageIndex = -1
+		index     int
+	)
+
+	return newStream(func() (Indexed[T], error) {
+		for index >= len(page) {
+			if !cursor.Next() {
+				return Indexed[T]{}, ErrStop
+			}
+
+			result, err := cursor.Get()
+			if err != nil {
+				return Indexed[T]{}, err
+			}
+
+			page, index = result, 0
+			pageIndex++
+		}
+
+		item := Indexed[T]{Page: pageIndex, Index: index, Item: page[index]}
+		index++
+		return item, nil
+	}, func() {
+		cursor.Reset()
+		page, pageIndex, index = nil, -1, 0
+	})
+}
+
+// Items returns stream of items of pages fetched by the cursor.
+func Items[Input, T any](cursor *Cursor[Input, []T]) *Stream[T] {
+	enumerated := Enumerate(cursor)
+
+	return newStream(func() (T, error) {
+		item, err := enumerated.Get()
+		return item.Item, err
+	}, enumerated.Reset)
+}
@@ -0,0 +1,128 @@
+package iter_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestEnumerate(t *testing.T) {
+	mockServer := httptest.NewServer(MockAPIHandler(5))
+	defer mockServer.Close()
+
+	stream := iter.Enumerate(simpleIterator(mockServer))
+
+	var results []iter.Indexed[Record]
+	err := stream.Iterate(func(value iter.Indexed[Record]) error {
+		results = append(results, value)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []iter.Indexed[Record]{
+		{Page: 0, Index: 0, Item: Record{1}},
+		{Page: 0, Index: 1, Item: Record{2}},
+		{Page: 1, Index: 0, Item: Record{3}},
+		{Page: 1, Index: 1, Item: Record{4}},
+		{Page: 2, Index: 0, Item: Record{5}},
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("unexpected results: got %+v, want %+v", results, expected)
+	}
+
+	t.Run("Get on depleted stream", func(t *testing.T) {
+		if _, err := stream.Get(); !errors.Is(err, iter.ErrStop) {
+			t.Errorf("expected ErrStop, got %+v", err)
+		}
+		if stream.Next() {
+			t.Errorf("Next should return false on depleted stream")
+		}
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		stream.Reset()
+		value, err := stream.Get()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(value, expected[0]) {
+			t.Errorf("expected first item again, got %+v", value)
+		}
+	})
+}
+
+func TestItems(t *testing.T) {
+	mockServer := httptest.NewServer(MockAPIHandler(3))
+	defer mockServer.Close()
+
+	stream := iter.Items(simpleIterator(mockServer))
+
+	var results []Record
+	for stream.Next() {
+		record, err := stream.Get()
+		if errors.Is(err, iter.ErrStop) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		results = append(results, record)
+	}
+
+	if !reflect.DeepEqual(results, []Record{{1}, {2}, {3}}) {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+func TestItemsServerError(t *testing.T) {
+	mockServer := httptest.NewServer(brokenServerHandler())
+	defer mockServer.Close()
+
+	stream := iter.Items(simpleIterator(mockServer))
+	err := stream.Iterate(func(value Record) error {
+		t.Fatalf("should not be called")
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "unexpected status code: 500") {
+		t.Fatalf("expected unexpected status code error, got %+v", err)
+	}
+}
+
+type countdown struct {
+	from, n int
+}
+
+func (c *countdown) Next() bool { return c.n > 0 }
+
+func (c *countdown) Get() (int, error) {
+	if c.n == 0 {
+		return 0, iter.ErrStop
+	}
+	c.n--
+	return c.n, nil
+}
+
+func (c *countdown) Reset() { c.n = c.from }
+
+func TestFromIterator(t *testing.T) {
+	var _ iter.Iterator[[]int] = iter.New(pagesConfig([][]int{{1}}))
+	var _ iter.Iterator[int] = iter.Items(iter.New(pagesConfig([][]int{{1}})))
+
+	stream := iter.FromIterator[int](&countdown{from: 3, n: 3})
+	values, err := stream.Collect()
+	if err != nil || !reflect.DeepEqual(values, []int{2, 1, 0}) {
+		t.Fatalf("unexpected values: %v, %v", values, err)
+	}
+
+	stream.Reset()
+	values, err = iter.Until(stream, func(value int) bool { return value == 0 }).Collect()
+	if err != nil || !reflect.DeepEqual(values, []int{2, 1}) {
+		t.Errorf("unexpected values after reset: %v, %v", values, err)
+	}
+}
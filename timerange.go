@@ -0,0 +1,90 @@
+package iter
+
+import "time"
+
+// TimeRange is the range of time [From, To). Zero bounds are open.
+type TimeRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// Contains reports whether t is in the range.
+func (r TimeRange) Contains(t time.Time) bool {
+	return (r.From.IsZero() || !t.Before(r.From)) && (r.To.IsZero() || t.Before(r.To))
+}
+
+// TimeOrder tells how items of pages are ordered by time.
+type TimeOrder int
+
+const (
+	// Unordered items are filtered, but every page is fetched.
+	Unordered TimeOrder = iota
+	// Ascending items stop the iteration once an item after the range is
+	// fetched.
+	Ascending
+	// Descending items stop the iteration once an item before the range
+	// is fetched.
+	Descending
+)
+
+// TimeFilterConfig configures [TimeFilter].
+type TimeFilterConfig[Input, T any] struct {
+	Range TimeRange
+	// Time returns the timestamp of an item.
+	Time func(item T) time.Time
+	// Order of items, used to stop fetching pages outside of the range.
+	Order TimeOrder
+	// Push is optional. It returns input narrowed to the range, e.g. with
+	// "since" and "until" query parameters, and is applied to every input
+	// before fetching.
+	Push func(input Input, r TimeRange) Input
+}
+
+// TimeFilter returns middleware keeping only items within the time
+// range. The range is pushed to the server with Push when possible, and
+// the iteration stops once the order of items tells that the remaining
+// pages are outside of the range.
+func TimeFilter[Input, T any](config TimeFilterConfig[Input, T]) Middleware[Input, []T] {
+	return func(c Config[Input, []T]) Config[Input, []T] {
+		var page []T
+
+		c.wrapFetch(func(fetchNext func(input Input) ([]T, error)) func(input Input) ([]T, error) {
+			return func(input Input) ([]T, error) {
+				if config.Push != nil {
+					input = config.Push(input, config.Range)
+				}
+
+				var err error
+				page, err = fetchNext(input)
+				if err != nil {
+					return nil, err
+				}
+
+				var items []T
+				for _, item := range page {
+					if config.Range.Contains(config.Time(item)) {
+						items = append(items, item)
+					}
+				}
+				return items, nil
+			}
+		})
+
+		hasNext := c.HasNext
+		c.HasNext = func([]T) (Input, bool) {
+			input, ok := hasNext(page)
+			for _, item := range page {
+				t := config.Time(item)
+				switch {
+				case config.Order == Ascending && !config.Range.To.IsZero() && !t.Before(config.Range.To):
+					return input, false
+				case config.Order == Descending && !config.Range.From.IsZero() && t.Before(config.Range.From):
+					return input, false
+				}
+			}
+			return input, ok
+		}
+
+		return c
+	}
+}
@@ -0,0 +1,74 @@
+package iter_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.teddydd.me/iter"
+)
+
+func TestTimeFilter(t *testing.T) {
+	day := func(n int) time.Time {
+		return time.Date(2024, 1, n, 0, 0, 0, 0, time.UTC)
+	}
+	r := iter.TimeRange{From: day(3), To: day(6)}
+
+	tests := []struct {
+		name    string
+		pages   [][]int
+		order   iter.TimeOrder
+		fetches int
+	}{
+		{"ascending", [][]int{{1, 2}, {3, 4}, {5, 6}, {7, 8}}, iter.Ascending, 3},
+		{"descending", [][]int{{8, 7}, {6, 5}, {4, 3}, {2, 1}, {0}}, iter.Descending, 4},
+		{"unordered", [][]int{{8, 1}, {3, 4}, {5, 2}, {7, 6}}, iter.Unordered, 4},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var fetches int
+			var pushed []iter.TimeRange
+			config := pagesConfig(tc.pages)
+			fetchNext := config.FetchNext
+			config.FetchNext = func(input int) ([]int, error) {
+				fetches++
+				return fetchNext(input)
+			}
+
+			iterator := iter.New(iter.Use(config, iter.TimeFilter(iter.TimeFilterConfig[int, int]{
+				Range: r,
+				Time:  func(n int) time.Time { return day(n) },
+				Order: tc.order,
+				Push: func(input int, r iter.TimeRange) int {
+					pushed = append(pushed, r)
+					return input
+				},
+			})))
+
+			var days []int
+			err := iterator.Iterate(func(page []int) error {
+				days = append(days, page...)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, d := range days {
+				if d < 3 || d >= 6 {
+					t.Errorf("day %d outside of range", d)
+				}
+			}
+			if len(days) != 3 {
+				t.Errorf("unexpected days: %v", days)
+			}
+			if fetches != tc.fetches {
+				t.Errorf("expected %d fetches, got %d", tc.fetches, fetches)
+			}
+			if len(pushed) != fetches || !reflect.DeepEqual(pushed[0], r) {
+				t.Errorf("range not pushed to every fetch: %+v", pushed)
+			}
+		})
+	}
+}
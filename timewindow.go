@@ -0,0 +1,84 @@
+package iter
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrTooManyResults should be returned (possibly wrapped) by fetch
+// function of [NewTimeWindows] when the window holds more results than
+// the API is able to return.
+var ErrTooManyResults = errors.New("too many results in window")
+
+// ErrInvalidTimeWindow is returned by cursors of [NewTimeWindows] if
+// Size is not positive or MinSize is negative.
+var ErrInvalidTimeWindow = errors.New("time window size must be positive")
+
+// TimeWindow is a half-open time range [Start, End).
+type TimeWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// WindowPage is a Result fetched for a time window.
+type WindowPage[Result any] struct {
+	Window TimeWindow
+	Result Result
+}
+
+// TimeWindowConfig configures a cursor over time range pagination.
+type TimeWindowConfig[Result any] struct {
+	// Start and End bound the whole iteration.
+	Start time.Time
+	End   time.Time
+	// Size is the length of a single window.
+	Size time.Duration
+	// MinSize is optional. When set, windows for which Fetch returns
+	// ErrTooManyResults are split in half as long as the halves are
+	// not shorter than MinSize.
+	MinSize time.Duration
+	// Fetch should fetch Result for the window.
+	Fetch func(window TimeWindow) (Result, error)
+}
+
+// NewTimeWindows creates a cursor for APIs that paginate only by time
+// range. It splits the range into windows of given size and fetches them
+// in order.
+func NewTimeWindows[Result any](
+	config TimeWindowConfig[Result],
+) *Cursor[TimeWindow, WindowPage[Result]] {
+	window := func(start time.Time) TimeWindow {
+		end := start.Add(config.Size)
+		if end.After(config.End) {
+			end = config.End
+		}
+		return TimeWindow{Start: start, End: end}
+	}
+
+	return New(Config[TimeWindow, WindowPage[Result]]{
+		HasNext: func(result WindowPage[Result]) (TimeWindow, bool) {
+			return window(result.Window.End), result.Window.End.Before(config.End)
+		},
+		FetchNext: func(input TimeWindow) (WindowPage[Result], error) {
+			if config.Size <= 0 || config.MinSize < 0 {
+				return WindowPage[Result]{Window: input}, ErrInvalidTimeWindow
+			}
+			for {
+				result, err := config.Fetch(input)
+				if err == nil {
+					return WindowPage[Result]{Window: input, Result: result}, nil
+				}
+
+				half := input.End.Sub(input.Start) / 2
+				if config.MinSize == 0 || half < config.MinSize || !errors.Is(err, ErrTooManyResults) {
+					return WindowPage[Result]{Window: input, Result: result}, err
+				}
+
+				input.End = input.Start.Add(half)
+			}
+		},
+		GetFirstInput: func() TimeWindow {
+			return window(config.Start)
+		},
+	})
+}
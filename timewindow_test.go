@@ -0,0 +1,101 @@
+package iter_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.teddydd.me/iter"
+)
+
+func TestTimeWindows(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := []time.Time{
+		start.Add(1 * time.Hour),
+		start.Add(2 * time.Hour),
+		start.Add(3 * time.Hour),
+		start.Add(13 * time.Hour),
+		start.Add(30 * time.Hour),
+	}
+
+	fetch := func(window iter.TimeWindow) ([]time.Time, error) {
+		var result []time.Time
+		for _, event := range events {
+			if !event.Before(window.Start) && event.Before(window.End) {
+				result = append(result, event)
+			}
+		}
+		if len(result) > 2 {
+			return nil, fmt.Errorf("window %v: %w", window, iter.ErrTooManyResults)
+		}
+		return result, nil
+	}
+
+	t.Run("split windows", func(t *testing.T) {
+		iterator := iter.NewTimeWindows(iter.TimeWindowConfig[[]time.Time]{
+			Start:   start,
+			End:     start.Add(36 * time.Hour),
+			Size:    24 * time.Hour,
+			MinSize: time.Hour,
+			Fetch:   fetch,
+		})
+
+		var windows []iter.TimeWindow
+		var results []time.Time
+		err := iterator.Iterate(func(response iter.WindowPage[[]time.Time]) error {
+			windows = append(windows, response.Window)
+			results = append(results, response.Result...)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if !reflect.DeepEqual(results, events) {
+			t.Errorf("unexpected results: %+v", results)
+		}
+
+		expected := []iter.TimeWindow{
+			{Start: start, End: start.Add(3 * time.Hour)},
+			{Start: start.Add(3 * time.Hour), End: start.Add(27 * time.Hour)},
+			{Start: start.Add(27 * time.Hour), End: start.Add(36 * time.Hour)},
+		}
+		if !reflect.DeepEqual(windows, expected) {
+			t.Errorf("unexpected windows: got %+v, want %+v", windows, expected)
+		}
+	})
+
+	t.Run("without splitting", func(t *testing.T) {
+		iterator := iter.NewTimeWindows(iter.TimeWindowConfig[[]time.Time]{
+			Start: start,
+			End:   start.Add(36 * time.Hour),
+			Size:  24 * time.Hour,
+			Fetch: fetch,
+		})
+		err := iterator.Iterate(func(response iter.WindowPage[[]time.Time]) error {
+			return nil
+		})
+		if !errors.Is(err, iter.ErrTooManyResults) {
+			t.Fatalf("expected ErrTooManyResults, got %+v", err)
+		}
+	})
+}
+
+func TestTimeWindowsInvalid(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, sizes := range [][2]time.Duration{{0, 0}, {-time.Hour, 0}, {time.Hour, -time.Minute}} {
+		cursor := iter.NewTimeWindows(iter.TimeWindowConfig[int]{
+			Start:   start,
+			End:     start.Add(24 * time.Hour),
+			Size:    sizes[0],
+			MinSize: sizes[1],
+			Fetch: func(window iter.TimeWindow) (int, error) {
+				return 0, iter.ErrTooManyResults
+			},
+		})
+		if _, err := cursor.Collect(); !errors.Is(err, iter.ErrInvalidTimeWindow) {
+			t.Errorf("size %v, min size %v: unexpected error: %v", sizes[0], sizes[1], err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package iter
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// TopK returns the k largest values of the stream according to less,
+// sorted from the largest. Pass a reversed less to get the k smallest
+// values. Only k values are kept in memory. If the stream fails, the
+// top values seen before the failure are returned along with the error.
+func TopK[T any](stream *Stream[T], k int, less func(a, b T) bool) ([]T, error) {
+	top := &topHeap[T]{less: less}
+
+	err := stream.Iterate(func(value T) error {
+		if k <= 0 {
+			return ErrStop
+		}
+		if top.Len() < k {
+			heap.Push(top, value)
+		} else if less(top.values[0], value) {
+			top.values[0] = value
+			heap.Fix(top, 0)
+		}
+		return nil
+	})
+
+	sort.SliceStable(top.values, func(i, j int) bool {
+		return less(top.values[j], top.values[i])
+	})
+	return top.values, err
+}
+
+// topHeap is a min-heap of the largest values.
+type topHeap[T any] struct {
+	values []T
+	less   func(a, b T) bool
+}
+
+func (h *topHeap[T]) Len() int {
+	return len(h.values)
+}
+
+func (h *topHeap[T]) Less(i, j int) bool {
+	return h.less(h.values[i], h.values[j])
+}
+
+func (h *topHeap[T]) Swap(i, j int) {
+	h.values[i], h.values[j] = h.values[j], h.values[i]
+}
+
+func (h *topHeap[T]) Push(x any) {
+	h.values = append(h.values, x.(T))
+}
+
+func (h *topHeap[T]) Pop() any {
+	last := h.values[len(h.values)-1]
+	h.values = h.values[:len(h.values)-1]
+	return last
+}
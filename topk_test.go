@@ -0,0 +1,40 @@
+package iter_test
+
+import (
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestTopK(t *testing.T) {
+	scores := iter.Items(iter.New(pagesConfig([][]int{{5, 1, 9}, {3, 7}, {8, 2, 6, 4}})))
+	less := func(a, b int) bool {
+		return a < b
+	}
+
+	top, err := iter.TopK(scores, 3, less)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(top, []int{9, 8, 7}) {
+		t.Errorf("unexpected top: %v", top)
+	}
+
+	scores.Reset()
+	bottom, err := iter.TopK(scores, 2, func(a, b int) bool {
+		return less(b, a)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(bottom, []int{1, 2}) {
+		t.Errorf("unexpected bottom: %v", bottom)
+	}
+
+	scores.Reset()
+	all, _ := iter.TopK(scores, 20, less)
+	if len(all) != 9 || all[0] != 9 || all[8] != 1 {
+		t.Errorf("unexpected values: %v", all)
+	}
+}
@@ -0,0 +1,29 @@
+package iter
+
+// Until returns stream of values of the stream preceding the first value
+// for which stop returns true. No more values are pulled from the stream
+// after that, so the underlying cursor stops fetching pages, e.g.
+//
+//	recent := iter.Until(iter.Items(cursor), func(event Event) bool {
+//		return event.Created.Before(cutoff)
+//	})
+func Until[T any](stream *Stream[T], stop func(value T) bool) *Stream[T] {
+	var stopped bool
+
+	return newStream(func() (T, error) {
+		var zero T
+		if stopped {
+			return zero, ErrStop
+		}
+
+		value, err := stream.Get()
+		if err == nil && stop(value) {
+			stopped = true
+			return zero, ErrStop
+		}
+		return value, err
+	}, func() {
+		stopped = false
+		stream.Reset()
+	})
+}
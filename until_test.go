@@ -0,0 +1,35 @@
+package iter_test
+
+import (
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestUntil(t *testing.T) {
+	var fetches int
+	config := pagesConfig([][]int{{9, 8}, {7, 6}, {5, 4}, {3, 2}})
+	fetchNext := config.FetchNext
+	config.FetchNext = func(input int) ([]int, error) {
+		fetches++
+		return fetchNext(input)
+	}
+
+	stream := iter.Until(iter.Items(iter.New(config)), func(n int) bool {
+		return n < 6
+	})
+
+	expected := []int{9, 8, 7, 6}
+	if results := collect(t, stream); !reflect.DeepEqual(results, expected) {
+		t.Errorf("unexpected results: got %+v, want %+v", results, expected)
+	}
+	if fetches != 3 {
+		t.Errorf("expected 3 fetches, got %d", fetches)
+	}
+
+	stream.Reset()
+	if results := collect(t, stream); !reflect.DeepEqual(results, expected) {
+		t.Errorf("unexpected results after reset: got %+v, want %+v", results, expected)
+	}
+}
@@ -0,0 +1,93 @@
+package iter
+
+import (
+	"net/url"
+	"strings"
+)
+
+// ResolveURL resolves possibly relative reference, such as a next link,
+// against base URL.
+func ResolveURL(base, ref string) (string, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	return b.ResolveReference(r).String(), nil
+}
+
+// SetQuery returns rawURL with query parameters replaced by params, so
+// page tokens can be swapped without string surgery:
+//
+//	next, err := iter.SetQuery(input, url.Values{"page_token": {token}})
+func SetQuery(rawURL string, params url.Values) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// NormalizeURL returns rawURL in canonical form, so URLs can be compared
+// or used as keys: scheme and host are lower case, default ports and
+// fragments are removed, empty path becomes "/" and query parameters
+// are sorted.
+func NormalizeURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if port := u.Port(); (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		u.Host = strings.TrimSuffix(u.Host, ":"+port)
+	}
+	if u.Path == "" && u.Host != "" {
+		u.Path = "/"
+	}
+	u.Fragment, u.RawFragment = "", ""
+	u.RawQuery = u.Query().Encode()
+	return u.String(), nil
+}
+
+// RedactQuery returns function for [Config.Redact] masking values of
+// the query parameters and the password of URL inputs, all parameters
+// if no keys are given.
+func RedactQuery(keys ...string) func(rawURL string) string {
+	return func(rawURL string) string {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return "[unparsable URL]"
+		}
+
+		query := u.Query()
+		for key := range query {
+			if len(keys) > 0 && !contains(keys, key) {
+				continue
+			}
+			for i := range query[key] {
+				query[key][i] = "REDACTED"
+			}
+		}
+		u.RawQuery = query.Encode()
+		return u.Redacted()
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package iter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrStalled is returned by fetches of cursors using [Watchdog] that
+// did not complete in time.
+var ErrStalled = errors.New("fetch stalled")
+
+// Watchdog returns middleware canceling fetches that do not complete
+// within timeout, protecting against servers that accept connections
+// but never respond. Stalled fetches are retried up to retries times
+// before failing with ErrStalled. Clock is used for timing, SystemClock
+// if nil.
+//
+// Stalled fetches are canceled through their context, see
+// [Config.FetchNextContext]. Attempts never overlap: the next attempt
+// waits for the canceled one to return, so fetches that ignore the
+// context delay it until they complete.
+func Watchdog[Input, Result any](clock Clock, timeout time.Duration, retries int) Middleware[Input, Result] {
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	type outcome struct {
+		result Result
+		err    error
+	}
+
+	return func(config Config[Input, Result]) Config[Input, Result] {
+		// running is closed once the last canceled attempt returns.
+		var running chan struct{}
+
+		wrap := func(fetch func(ctx context.Context, input Input) (Result, error)) func(ctx context.Context, input Input) (Result, error) {
+			return func(ctx context.Context, input Input) (Result, error) {
+				for attempt := 0; ; attempt++ {
+					if running != nil {
+						select {
+						case <-running:
+							running = nil
+						case <-ctx.Done():
+							var zero Result
+							return zero, ctx.Err()
+						}
+					}
+
+					attemptCtx, cancel := context.WithCancel(ctx)
+					done := make(chan outcome, 1)
+					finished := make(chan struct{})
+					go func() {
+						defer close(finished)
+						result, err := fetch(attemptCtx, input)
+						done <- outcome{result: result, err: err}
+					}()
+
+					select {
+					case o := <-done:
+						cancel()
+						return o.result, o.err
+					case <-clock.After(timeout):
+					}
+					cancel()
+					running = finished
+
+					if attempt >= retries {
+						var zero Result
+						return zero, fmt.Errorf("%w: no result within %v after %d attempts", ErrStalled, timeout, attempt+1)
+					}
+				}
+			}
+		}
+
+		config.wrapFetchContext(wrap, wrap)
+		return config
+	}
+}
@@ -0,0 +1,135 @@
+package iter_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.teddydd.me/iter"
+	"go.teddydd.me/iter/itertest"
+)
+
+// hangingConfig returns config whose first count fetches hang until
+// canceled. Fetches update a plain counter, so overlapping attempts are
+// reported by the race detector.
+func hangingConfig(count int, started chan<- struct{}) (iter.Config[int, []int], *int) {
+	var attempts int
+	config := pagesConfig([][]int{{1}, {2}})
+	fetchNext := config.FetchNext
+	config.FetchNext = nil
+	config.FetchNextContext = func(ctx context.Context, input int) ([]int, error) {
+		attempts++
+		if attempts <= count {
+			started <- struct{}{}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return fetchNext(input)
+	}
+	return config, &attempts
+}
+
+func TestWatchdog(t *testing.T) {
+	clock := itertest.NewClock(time.Now())
+	started := make(chan struct{}, 1)
+	config, attempts := hangingConfig(1, started)
+	iterator := iter.New(iter.Use(config, iter.Watchdog[int, []int](clock, time.Minute, 1)))
+
+	done := make(chan struct{})
+	var (
+		result []int
+		err    error
+	)
+	go func() {
+		result, err = iterator.Get()
+		close(done)
+	}()
+
+	<-started
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	<-done
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result, []int{1}) || *attempts != 2 {
+		t.Errorf("expected stalled fetch to be retried, got %v after %d attempts", result, *attempts)
+	}
+}
+
+func TestWatchdogStalled(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	clock := itertest.NewClock(time.Now())
+	started := make(chan struct{}, 2)
+	config, attempts := hangingConfig(2, started)
+	iterator := iter.New(iter.Use(config, iter.Watchdog[int, []int](clock, time.Minute, 1)))
+
+	done := make(chan error)
+	go func() {
+		_, err := iterator.Get()
+		done <- err
+	}()
+	for i := 0; i < 2; i++ {
+		<-started
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute)
+	}
+	if err := <-done; !errors.Is(err, iter.ErrStalled) {
+		t.Fatalf("expected ErrStalled, got %v", err)
+	}
+
+	// canceled fetches return without being unblocked by hand
+	checkGoroutines(t, before)
+	if _, err := iterator.Get(); err != nil || *attempts != 3 {
+		t.Errorf("unexpected fetch after stall: %v after %d attempts", err, *attempts)
+	}
+}
+
+func TestWatchdogIgnoredContext(t *testing.T) {
+	clock := itertest.NewClock(time.Now())
+	hang := make(chan struct{})
+
+	var attempts, running, overlapping int32
+	config := pagesConfig([][]int{{1}})
+	fetchNext := config.FetchNext
+	config.FetchNext = func(input int) ([]int, error) {
+		if atomic.AddInt32(&running, 1) > 1 {
+			atomic.AddInt32(&overlapping, 1)
+		}
+		defer atomic.AddInt32(&running, -1)
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			<-hang
+		}
+		return fetchNext(input)
+	}
+	iterator := iter.New(iter.Use(config, iter.Watchdog[int, []int](clock, time.Minute, 1)))
+
+	done := make(chan error)
+	go func() {
+		_, err := iterator.Get()
+		done <- err
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+
+	// the retry waits for the stalled fetch that ignores the context
+	select {
+	case err := <-done:
+		t.Fatalf("retry did not wait for the stalled fetch: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(hang)
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 2 || overlapping != 0 {
+		t.Errorf("unexpected attempts: %d, overlapping %d", attempts, overlapping)
+	}
+}
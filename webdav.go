@@ -0,0 +1,168 @@
+package iter
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrSyncTokenInvalid is returned by [NewSyncCollection] when the server
+// no longer accepts the sync token and a full resync is needed.
+var ErrSyncTokenInvalid = errors.New("sync token invalid, full resync needed")
+
+// SyncCollectionConfig configures WebDAV sync-collection reports
+// (RFC 6578), as supported by CalDAV and CardDAV servers.
+type SyncCollectionConfig struct {
+	HTTP
+	// URL of the collection.
+	URL string
+	// Token returned by the previous sync, empty for the initial sync.
+	Token string
+	// Limit is the number of results requested per page, zero leaves it
+	// up to the server.
+	Limit int
+}
+
+// SyncChange is a member of the collection changed since the sync token.
+type SyncChange struct {
+	Href string
+	ETag string
+	// Deleted reports whether the member was removed.
+	Deleted bool
+}
+
+// SyncPage is a response to a sync-collection report.
+type SyncPage struct {
+	Changes []SyncChange
+	// Token to persist for the next sync.
+	Token string
+	// Truncated reports whether the server has more changes.
+	Truncated bool
+}
+
+type davLimit struct {
+	NResults int `xml:"nresults"`
+}
+
+type davSyncCollection struct {
+	XMLName   xml.Name  `xml:"DAV: sync-collection"`
+	SyncToken string    `xml:"sync-token"`
+	SyncLevel string    `xml:"sync-level"`
+	Limit     *davLimit `xml:"limit,omitempty"`
+	Prop      struct {
+		ETag struct{} `xml:"getetag"`
+	} `xml:"prop"`
+}
+
+type davMultistatus struct {
+	Responses []struct {
+		Href     string `xml:"DAV: href"`
+		Status   string `xml:"DAV: status"`
+		Propstat []struct {
+			Status string `xml:"DAV: status"`
+			ETag   string `xml:"DAV: prop>getetag"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+	SyncToken string `xml:"DAV: sync-token"`
+}
+
+type davError struct {
+	ValidSyncToken *struct{} `xml:"DAV: valid-sync-token"`
+}
+
+// davStatus returns the status code from a DAV:status element such as
+// "HTTP/1.1 404 Not Found".
+func davStatus(status string) string {
+	fields := strings.Fields(status)
+	if len(fields) < 2 {
+		return ""
+	}
+	return fields[1]
+}
+
+// NewSyncCollection creates a cursor over changes of a WebDAV collection.
+// Sync token is used as input. Pages are fetched while the server
+// reports truncated results, the token of the last page should be
+// persisted for the next sync. If the server rejects the token,
+// [ErrSyncTokenInvalid] is returned.
+func NewSyncCollection(config SyncCollectionConfig) *Cursor[string, SyncPage] {
+	return New(Config[string, SyncPage]{
+		HasNext: func(result SyncPage) (string, bool) {
+			return result.Token, result.Truncated
+		},
+		FetchNextContext: func(ctx context.Context, input string) (SyncPage, error) {
+			page := SyncPage{Token: input}
+
+			report := davSyncCollection{SyncToken: input, SyncLevel: "1"}
+			if config.Limit > 0 {
+				report.Limit = &davLimit{NResults: config.Limit}
+			}
+			body, err := xml.Marshal(report)
+			if err != nil {
+				return page, err
+			}
+
+			req, err := http.NewRequestWithContext(ctx, "REPORT", config.URL, bytes.NewReader(body))
+			if err != nil {
+				return page, err
+			}
+			req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+			req.Header.Set("Depth", "0")
+
+			resp, err := config.do(req)
+			if err != nil {
+				return page, err
+			}
+			defer resp.Body.Close()
+
+			data, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return page, err
+			}
+
+			if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusConflict {
+				var davErr davError
+				if xml.Unmarshal(data, &davErr) == nil && davErr.ValidSyncToken != nil {
+					return page, ErrSyncTokenInvalid
+				}
+			}
+			if resp.StatusCode != http.StatusMultiStatus {
+				return page, &StatusError{StatusCode: resp.StatusCode, URL: config.URL}
+			}
+
+			var multistatus davMultistatus
+			if err := xml.Unmarshal(data, &multistatus); err != nil {
+				return page, err
+			}
+
+			for _, response := range multistatus.Responses {
+				switch davStatus(response.Status) {
+				case "404":
+					page.Changes = append(page.Changes, SyncChange{Href: response.Href, Deleted: true})
+					continue
+				case "507":
+					page.Truncated = true
+					continue
+				}
+
+				change := SyncChange{Href: response.Href}
+				for _, propstat := range response.Propstat {
+					if davStatus(propstat.Status) == "200" {
+						change.ETag = propstat.ETag
+					}
+				}
+				page.Changes = append(page.Changes, change)
+			}
+			page.Token = multistatus.SyncToken
+
+			return page, nil
+		},
+		GetFirstInput: func() string {
+			return config.Token
+		},
+	})
+}
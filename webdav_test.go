@@ -0,0 +1,114 @@
+package iter_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func syncCollectionHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "REPORT" {
+			t.Errorf("unexpected method: %s", r.Method)
+		}
+
+		var report struct {
+			SyncToken string `xml:"DAV: sync-token"`
+			NResults  int    `xml:"DAV: limit>nresults"`
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&report); err != nil {
+			t.Errorf("decode report: %v", err)
+		}
+		if report.NResults != 2 {
+			t.Errorf("unexpected limit: %d", report.NResults)
+		}
+
+		switch report.SyncToken {
+		case "":
+			w.WriteHeader(http.StatusMultiStatus)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?>
+<d:multistatus xmlns:d="DAV:">
+  <d:response>
+    <d:href>/cal/1.ics</d:href>
+    <d:propstat>
+      <d:prop><d:getetag>"a"</d:getetag></d:prop>
+      <d:status>HTTP/1.1 200 OK</d:status>
+    </d:propstat>
+  </d:response>
+  <d:response>
+    <d:href>/cal/2.ics</d:href>
+    <d:status>HTTP/1.1 404 Not Found</d:status>
+  </d:response>
+  <d:response>
+    <d:href>/cal/</d:href>
+    <d:status>HTTP/1.1 507 Insufficient Storage</d:status>
+  </d:response>
+  <d:sync-token>token-1</d:sync-token>
+</d:multistatus>`)
+		case "token-1":
+			w.WriteHeader(http.StatusMultiStatus)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?>
+<d:multistatus xmlns:d="DAV:">
+  <d:response>
+    <d:href>/cal/3.ics</d:href>
+    <d:propstat>
+      <d:prop><d:getetag>"c"</d:getetag></d:prop>
+      <d:status>HTTP/1.1 200 OK</d:status>
+    </d:propstat>
+  </d:response>
+  <d:sync-token>token-2</d:sync-token>
+</d:multistatus>`)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?>
+<d:error xmlns:d="DAV:"><d:valid-sync-token/></d:error>`)
+		}
+	}
+}
+
+func TestNewSyncCollection(t *testing.T) {
+	mockServer := httptest.NewServer(syncCollectionHandler(t))
+	defer mockServer.Close()
+
+	t.Run("initial sync", func(t *testing.T) {
+		iterator := iter.NewSyncCollection(iter.SyncCollectionConfig{URL: mockServer.URL, Limit: 2})
+
+		var changes []iter.SyncChange
+		var token string
+		err := iterator.Iterate(func(response iter.SyncPage) error {
+			changes = append(changes, response.Changes...)
+			token = response.Token
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := []iter.SyncChange{
+			{Href: "/cal/1.ics", ETag: `"a"`},
+			{Href: "/cal/2.ics", Deleted: true},
+			{Href: "/cal/3.ics", ETag: `"c"`},
+		}
+		if !reflect.DeepEqual(changes, expected) {
+			t.Errorf("unexpected changes: %+v", changes)
+		}
+		if token != "token-2" {
+			t.Errorf("unexpected token: %s", token)
+		}
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		iterator := iter.NewSyncCollection(iter.SyncCollectionConfig{URL: mockServer.URL, Token: "expired", Limit: 2})
+
+		_, err := iterator.Get()
+		if !errors.Is(err, iter.ErrSyncTokenInvalid) {
+			t.Errorf("expected ErrSyncTokenInvalid, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package iter
+
+import "errors"
+
+// ErrInvalidWindow is returned by [Window] streams if size or step is
+// not positive.
+var ErrInvalidWindow = errors.New("window size and step must be positive")
+
+// Window returns stream of windows of size consecutive values of the
+// stream, starting every step values. Windows span page boundaries.
+// Trailing values that do not fill a whole window are not yielded.
+func Window[T any](stream *Stream[T], size, step int) *Stream[[]T] {
+	var (
+		buffer []T
+		skip   int
+	)
+
+	return newStream(func() ([]T, error) {
+		if size <= 0 || step <= 0 {
+			return nil, ErrInvalidWindow
+		}
+		for len(buffer) < size {
+			value, err := stream.Get()
+			if err != nil {
+				return nil, err
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+			buffer = append(buffer, value)
+		}
+
+		window := make([]T, size)
+		copy(window, buffer)
+
+		if step < size {
+			buffer = append(buffer[:0], buffer[step:]...)
+		} else {
+			skip = step - size
+			buffer = buffer[:0]
+		}
+
+		return window, nil
+	}, func() {
+		stream.Reset()
+		buffer, skip = nil, 0
+	})
+}
@@ -0,0 +1,60 @@
+package iter_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.teddydd.me/iter"
+)
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		step     int
+		expected [][]int
+	}{
+		{
+			name:     "overlapping",
+			size:     3,
+			step:     1,
+			expected: [][]int{{0, 1, 2}, {1, 2, 3}, {2, 3, 4}, {3, 4, 5}, {4, 5, 6}},
+		},
+		{
+			name:     "tumbling",
+			size:     2,
+			step:     2,
+			expected: [][]int{{0, 1}, {2, 3}, {4, 5}},
+		},
+		{
+			name:     "gaps",
+			size:     2,
+			step:     3,
+			expected: [][]int{{0, 1}, {3, 4}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			stream := iter.Window(numbers(7, 2), tc.size, tc.step)
+			if results := collect(t, stream); !reflect.DeepEqual(results, tc.expected) {
+				t.Errorf("unexpected results: got %+v, want %+v", results, tc.expected)
+			}
+
+			stream.Reset()
+			if results := collect(t, stream); !reflect.DeepEqual(results, tc.expected) {
+				t.Errorf("unexpected results after reset: got %+v, want %+v", results, tc.expected)
+			}
+		})
+	}
+}
+
+func TestWindowInvalid(t *testing.T) {
+	for _, args := range [][2]int{{0, 1}, {-1, 1}, {2, 0}, {2, -1}} {
+		stream := iter.Window(numbers(7, 2), args[0], args[1])
+		if _, err := stream.Get(); !errors.Is(err, iter.ErrInvalidWindow) {
+			t.Errorf("size %d, step %d: unexpected error: %v", args[0], args[1], err)
+		}
+	}
+}